@@ -0,0 +1,122 @@
+//
+// alias.go
+//
+package yaml
+
+import (
+	"log"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+
+var (
+	aliasesMu      sync.RWMutex
+	aliases        = make(map[string]string)
+	deprecatedKeys = make(map[string]string)
+	warnedKeys     = make(map[string]struct{})
+)
+
+
+//
+// Register an alias from the old key to the new key.
+// Loaded configs copy the value of the old key to the new key, so Get, Decode and DebugHandler see the new key.
+// Lookups in configs loaded before the registration fall back to the old key.
+// The old key is registered as deprecated.
+//
+func RegisterAlias(oldKey, newKey string) {
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	aliases[newKey] = oldKey
+	if _, ok := deprecatedKeys[oldKey]; !ok {
+		deprecatedKeys[oldKey] = "Use " + newKey + " instead."
+	}
+	settingsGen.Add(1)
+}
+
+
+//
+// Register a deprecated key.
+// Loading a file which uses the key logs the message once.
+//
+func RegisterDeprecated(key, message string) {
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	deprecatedKeys[key] = message
+}
+
+
+//
+// Get the old key registered for the new key.
+//
+func lookupAlias(key string) (string, bool) {
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+	oldKey, ok := aliases[key]
+	return oldKey, ok
+}
+
+
+//
+// Copy the value of the old key to the new key of each alias, unless the new key is set.
+// The origins of the old key are copied to the new key too.
+//
+func applyAliases(result *loadResult) {
+	aliasesMu.RLock()
+	pairs := make(map[string]string, len(aliases))
+	for newKey, oldKey := range aliases {
+		pairs[newKey] = oldKey
+	}
+	aliasesMu.RUnlock()
+
+	option := getNormalizeOption()
+	for newKey, oldKey := range pairs {
+		newKey, oldKey = option.normalizeKey(newKey), option.normalizeKey(oldKey)
+		newPath := strings.Split(newKey, ".")
+		if lookupConfig(result.config, newPath) != nil {
+			continue
+		}
+		value := lookupConfig(result.config, strings.Split(oldKey, "."))
+		if value == nil {
+			continue
+		}
+		setValue(result.config, newPath, copyValue(value))
+		for key, origin := range result.origins {
+			if key == oldKey {
+				result.origins[newKey] = origin
+			} else if strings.HasPrefix(key, oldKey+".") {
+				result.origins[newKey+strings.TrimPrefix(key, oldKey)] = origin
+			}
+		}
+	}
+}
+
+
+//
+// Log a warning for each deprecated key used in the document.
+//
+func warnDeprecated(doc *yaml.Node, name string) {
+	aliasesMu.RLock()
+	empty := len(deprecatedKeys) == 0
+	aliasesMu.RUnlock()
+	if empty {
+		return
+	}
+
+	walkNode(doc, "", func(key string, keyNode, valueNode *yaml.Node) {
+		aliasesMu.Lock()
+		defer aliasesMu.Unlock()
+		message, ok := deprecatedKeys[key]
+		if !ok {
+			return
+		}
+		warnedKey := name + "\x00" + key
+		if _, ok := warnedKeys[warnedKey]; ok {
+			return
+		}
+		warnedKeys[warnedKey] = struct{}{}
+		log.Printf("[WARN] Config key %s is deprecated. %s (file: %s, line: %d)\n", key, message, name, keyNode.Line)
+	})
+}
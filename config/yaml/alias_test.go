@@ -0,0 +1,96 @@
+//
+// alias_test.go
+//
+package yaml_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test lookups falling back to the old key, and the deprecation warning.
+//
+func TestRegisterAlias(t *testing.T) {
+	yaml.RegisterAlias("db.addr", "database.host")
+
+	file, err := createTempYAMLFile(`
+db:
+  addr: old-host
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	store := yaml.NewStore(nil)
+	for i := 0; i < 2; i++ {
+		if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+			t.Fatalf("Failed to load. Error: %v\n", err)
+		}
+	}
+
+	if val := store.GetString("database.host"); val != "old-host" {
+		t.Errorf("Failed to read database.host value. Expected: old-host, actual: %s\n", val)
+	}
+	if n := strings.Count(buf.String(), "db.addr is deprecated"); n != 1 {
+		t.Errorf("Expected one warning, actual: %d\n%s", n, buf.String())
+	}
+	if !strings.Contains(buf.String(), "line: 3") {
+		t.Errorf("Warning does not contain the line.\n%s", buf.String())
+	}
+}
+
+
+//
+// Test Decode and DebugHandler seeing the new key of the alias.
+//
+func TestRegisterAlias_Decode(t *testing.T) {
+	yaml.RegisterAlias("cache.addr", "redis.host")
+
+	file, err := createTempYAMLFile(`
+cache:
+  addr: cache.local
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+
+	var redis struct {
+		Host string `yaml:"host"`
+	}
+	if err := store.Decode("redis", &redis); err != nil {
+		t.Fatalf("Failed to decode. Error: %v\n", err)
+	}
+	if redis.Host != "cache.local" {
+		t.Errorf("Failed to decode redis.host value. Expected: cache.local, actual: %s\n", redis.Host)
+	}
+	if origin, ok := store.OriginOf("redis.host"); !ok || origin.Line != 3 {
+		t.Errorf("Unexpected origin of redis.host: %+v\n", origin)
+	}
+
+	rec := httptest.NewRecorder()
+	yaml.NewDebugHandler(store).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/config?key=redis.host", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "cache.local") {
+		t.Errorf("Unexpected response: %d %s\n", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,597 @@
+//
+// main.go
+//
+// yamlcfg queries, merges and validates config files from the shell.
+//
+//   yamlcfg get -yaml base.yaml -yaml prod.yaml server.port
+//   yamlcfg merge -yaml base.yaml -yaml prod.yaml -format json
+//   yamlcfg validate -yaml config.yaml -schema schema.json
+//   yamlcfg explain -yaml base.yaml -yaml prod.yaml server.port
+//   yamlcfg diff old.yaml new.yaml
+//   yamlcfg keys -yaml config.yaml server
+//   yamlcfg encrypt -key-file config.key 's3cret'
+//
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+
+const usage = `Usage: yamlcfg <command> [options] [arguments]
+
+Commands:
+  get <key>          Print the value of the key
+  merge              Print the merged config
+  validate           Check duplicate and conflicting keys, and the schema given by -schema
+  explain <key>      Print the value of the key, where it was defined, and the value in each source
+  diff <old> <new>   Print the changed keys between two configs (exit status 1 if they differ)
+  keys [prefix]      Print the keys, optionally under the prefix
+  encrypt [value]    Encrypt the value (or stdin) for the config with -key-file, or print a new key with -genkey
+
+Run "yamlcfg <command> -h" for the options of a command.
+`
+
+
+//
+// options are the flags shared by the commands.
+//
+type options struct {
+	yamlPaths []string
+	sets      *yaml.FlagSource
+	envPrefix string
+	profile   string
+	format    string
+	keyFile   string
+	normalize bool
+	lenient   bool
+}
+
+
+//
+// errDiffer makes diff exit with the status 1 without an error message.
+// errUsage makes the command exit with the status 2 after the flag set printed the error.
+//
+var (
+	errDiffer = errors.New("Configs differ.")
+	errUsage  = errors.New("Invalid usage.")
+)
+
+
+//
+// Main.
+//
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+
+//
+// Run the command, and return the exit status.
+//
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	commands := map[string]func(args []string, stdout, stderr io.Writer) error{
+		"get": runGet,
+		"merge": runMerge,
+		"validate": runValidate,
+		"explain": runExplain,
+		"diff": runDiff,
+		"keys": runKeys,
+		"encrypt": runEncrypt,
+	}
+	name := args[0]
+	if name == "-h" || name == "-help" || name == "--help" || name == "help" {
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "Error: Unknown command. (command: %s)\n\n%s", name, usage)
+		return 2
+	}
+	switch err := command(args[1:], stdout, stderr); err {
+	case nil, flag.ErrHelp:
+		return 0
+	case errUsage:
+		return 2
+	case errDiffer:
+		return 1
+	default:
+		fmt.Fprintf(stderr, "Error: %s\n", strings.TrimSpace(err.Error()))
+		return 1
+	}
+}
+
+
+//
+// New flag set with the shared options.
+//
+func newFlagSet(name string, stderr io.Writer) (*flag.FlagSet, *options) {
+	opts := &options{
+		sets: yaml.NewFlagSource(),
+	}
+	fs := flag.NewFlagSet("yamlcfg " + name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Func("yaml", "Path or URL to yaml config file (can be specified multiple times)", func(s string) error {
+		opts.yamlPaths = append(opts.yamlPaths, s)
+		return nil
+	})
+	fs.Var(opts.sets, "set", "Override a config value as key=value (can be specified multiple times)")
+	fs.StringVar(&opts.envPrefix, "env", "", "Load env variables with the prefix (e.g. APP_)")
+	fs.StringVar(&opts.profile, "profile", "", "Comma separated profiles of the documents to load from multi-document files")
+	fs.StringVar(&opts.format, "format", "yaml", "Output format (yaml or json)")
+	fs.StringVar(&opts.keyFile, "key-file", "", "File of the base64 encoded key to decrypt or encrypt values")
+	fs.BoolVar(&opts.normalize, "normalize", false, "Match keys case-insensitively with - and _ as the same")
+	fs.BoolVar(&opts.lenient, "lenient", false, "Convert numeric and bool strings in the getters")
+	return fs, opts
+}
+
+
+//
+// Parse the flags.
+// The flag set prints the error and the usage, so the error is returned as errUsage.
+//
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return errUsage
+	}
+	return nil
+}
+
+
+//
+// Apply the global settings of the options.
+//
+func (o *options) apply() error {
+	if o.format != "yaml" && o.format != "json" {
+		return fmt.Errorf("Unknown format. (format: %s)", o.format)
+	}
+	if o.profile != "" {
+		var profiles []string
+		for _, p := range strings.Split(o.profile, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				profiles = append(profiles, p)
+			}
+		}
+		yaml.SelectDocuments(yaml.DefaultDocumentKey, profiles...)
+	}
+	if o.normalize {
+		yaml.SetNormalizeOption(yaml.NewNormalizeOption())
+	}
+	yaml.SetLenient(o.lenient)
+	if o.keyFile != "" {
+		key, err := yaml.ReadEncryptionKey(o.keyFile)
+		if err != nil {
+			return err
+		}
+		return yaml.SetDecryptionKey(key)
+	}
+	return nil
+}
+
+
+//
+// Get the sources of the options with their names, in the merged order.
+//
+func (o *options) sources() ([]yaml.Source, []string, error) {
+	if len(o.yamlPaths) == 0 {
+		return nil, nil, fmt.Errorf("Need at least one -yaml option.")
+	}
+	var sources []yaml.Source
+	var names []string
+	for _, path := range o.yamlPaths {
+		src, err := yaml.NewSource(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		sources = append(sources, src)
+		names = append(names, path)
+	}
+	if o.envPrefix != "" {
+		sources = append(sources, yaml.NewEnvSource(o.envPrefix))
+		names = append(names, "env:" + o.envPrefix)
+	}
+	if o.sets.String() != "" {
+		sources = append(sources, o.sets)
+		names = append(names, "flag")
+	}
+	return sources, names, nil
+}
+
+
+//
+// Load the merged config of the options.
+//
+func (o *options) load() (*yaml.Store, error) {
+	if err := o.apply(); err != nil {
+		return nil, err
+	}
+	sources, _, err := o.sources()
+	if err != nil {
+		return nil, err
+	}
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), sources...); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+
+//
+// Print the value of the key.
+//
+func runGet(args []string, stdout, stderr io.Writer) error {
+	fs, opts := newFlagSet("get", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("Need a key. (usage: yamlcfg get [options] <key>)")
+	}
+
+	store, err := opts.load()
+	if err != nil {
+		return err
+	}
+	value, err := yaml.GetFrom[interface{}](store, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return write(stdout, opts.format, value)
+}
+
+
+//
+// Print the merged config.
+//
+func runMerge(args []string, stdout, stderr io.Writer) error {
+	fs, opts := newFlagSet("merge", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	store, err := opts.load()
+	if err != nil {
+		return err
+	}
+	return write(stdout, opts.format, store.Config())
+}
+
+
+//
+// Check the duplicate and conflicting keys of the sources, and validate the merged config by the schema.
+//
+func runValidate(args []string, stdout, stderr io.Writer) error {
+	fs, opts := newFlagSet("validate", stderr)
+	schemaPath := fs.String("schema", "", "Path to the JSON Schema (in YAML or JSON) to validate the merged config")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := opts.apply(); err != nil {
+		return err
+	}
+	sources, _, err := opts.sources()
+	if err != nil {
+		return err
+	}
+	issues, err := yaml.NewLoader(sources...).Lint(context.Background())
+	if err != nil {
+		return err
+	}
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.Error())
+	}
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), sources...); err != nil {
+		// Strict loading fails with the issues already listed.
+		if len(issues) == 0 || strings.TrimSpace(err.Error()) != issues.Error() {
+			messages = append(messages, err.Error())
+		}
+	} else if *schemaPath != "" {
+		var errs yaml.ValidationErrors
+		if err := store.ValidateSchema(*schemaPath); errors.As(err, &errs) {
+			for _, e := range errs {
+				messages = append(messages, e.Error())
+			}
+		} else if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+
+	if len(messages) > 0 {
+		for _, message := range messages {
+			fmt.Fprintln(stdout, strings.TrimSpace(message))
+		}
+		return fmt.Errorf("Invalid config. (%d problems)", len(messages))
+	}
+	fmt.Fprintln(stdout, "OK")
+	return nil
+}
+
+
+//
+// Print the value of the key with its origin, and the value in each source.
+//
+func runExplain(args []string, stdout, stderr io.Writer) error {
+	fs, opts := newFlagSet("explain", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("Need a key. (usage: yamlcfg explain [options] <key>)")
+	}
+	key := fs.Arg(0)
+
+	store, err := opts.load()
+	if err != nil {
+		return err
+	}
+	value, err := yaml.GetFrom[interface{}](store, key)
+	if err != nil {
+		return err
+	}
+	type sourceValue struct {
+		Source string      `json:"source" yaml:"source"`
+		Value  interface{} `json:"value" yaml:"value"`
+	}
+	explanation := struct {
+		Key     string        `json:"key" yaml:"key"`
+		Value   interface{}   `json:"value" yaml:"value"`
+		Origin  string        `json:"origin,omitempty" yaml:"origin,omitempty"`
+		Sources []sourceValue `json:"sources" yaml:"sources"`
+	}{
+		Key: key,
+		Value: value,
+	}
+	if origin, ok := store.OriginOf(key); ok {
+		explanation.Origin = origin.String()
+	}
+
+	// The value defined by each source before merging.
+	sources, names, err := opts.sources()
+	if err != nil {
+		return err
+	}
+	for i, src := range sources {
+		config, err := yaml.NewLoader(src).Load(context.Background())
+		if err != nil {
+			return err
+		}
+		if v, err := yaml.GetFrom[interface{}](yaml.NewStore(config), key); err == nil {
+			explanation.Sources = append(explanation.Sources, sourceValue{Source: names[i], Value: v})
+		}
+	}
+	return write(stdout, opts.format, explanation)
+}
+
+
+//
+// Print the changed keys between two configs.
+//
+func runDiff(args []string, stdout, stderr io.Writer) error {
+	fs, opts := newFlagSet("diff", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("Need two configs. (usage: yamlcfg diff [options] <old> <new>)")
+	}
+	if err := opts.apply(); err != nil {
+		return err
+	}
+
+	var configs []map[string]interface{}
+	for _, path := range fs.Args() {
+		src, err := yaml.NewSource(path)
+		if err != nil {
+			return err
+		}
+		store := yaml.NewStore(nil)
+		if err := store.InitSources(context.Background(), src); err != nil {
+			return err
+		}
+		configs = append(configs, store.Config())
+	}
+
+	changes := yaml.Diff(configs[0], configs[1])
+	if opts.format == "json" {
+		type change struct {
+			Key string      `json:"key"`
+			Old interface{} `json:"old"`
+			New interface{} `json:"new"`
+		}
+		result := make([]change, len(changes))
+		for i, c := range changes {
+			result[i] = change{Key: c.Key, Old: c.Old, New: c.New}
+		}
+		if err := write(stdout, opts.format, result); err != nil {
+			return err
+		}
+	} else {
+		for _, c := range changes {
+			switch {
+			case c.Old == nil:
+				fmt.Fprintf(stdout, "+ %s: %s\n", c.Key, formatInline(c.New))
+			case c.New == nil:
+				fmt.Fprintf(stdout, "- %s: %s\n", c.Key, formatInline(c.Old))
+			default:
+				fmt.Fprintf(stdout, "~ %s: %s -> %s\n", c.Key, formatInline(c.Old), formatInline(c.New))
+			}
+		}
+	}
+	if len(changes) > 0 {
+		return errDiffer
+	}
+	return nil
+}
+
+
+//
+// Print the leaf keys, optionally under the prefix.
+//
+func runKeys(args []string, stdout, stderr io.Writer) error {
+	fs, opts := newFlagSet("keys", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	prefix := fs.Arg(0)
+
+	store, err := opts.load()
+	if err != nil {
+		return err
+	}
+	var keys []string
+	for _, c := range yaml.Diff(nil, store.Config()) {
+		if prefix == "" || c.Key == prefix || strings.HasPrefix(c.Key, prefix + ".") {
+			keys = append(keys, c.Key)
+		}
+	}
+	sort.Strings(keys)
+
+	if opts.format == "json" {
+		return write(stdout, opts.format, keys)
+	}
+	for _, key := range keys {
+		fmt.Fprintln(stdout, key)
+	}
+	return nil
+}
+
+
+//
+// Encrypt the value for the config, or generate a new key.
+//
+func runEncrypt(args []string, stdout, stderr io.Writer) error {
+	fs, opts := newFlagSet("encrypt", stderr)
+	genKey := fs.Bool("genkey", false, "Print a new base64 encoded key instead of encrypting")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *genKey {
+		key := make([]byte, yaml.EncryptionKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, base64.StdEncoding.EncodeToString(key))
+		return nil
+	}
+
+	if opts.keyFile == "" {
+		return fmt.Errorf("Need the -key-file option.")
+	}
+	key, err := yaml.ReadEncryptionKey(opts.keyFile)
+	if err != nil {
+		return err
+	}
+	var value string
+	if fs.NArg() > 0 {
+		value = fs.Arg(0)
+	} else {
+		bytes, err := io.ReadAll(bufio.NewReader(os.Stdin))
+		if err != nil {
+			return err
+		}
+		value = strings.TrimRight(string(bytes), "\r\n")
+	}
+	encrypted, err := yaml.Encrypt(value, key)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, encrypted)
+	return nil
+}
+
+
+//
+// Write the value in the format.
+//
+func write(w io.Writer, format string, value interface{}) error {
+	if format == "json" {
+		bytes, err := json.MarshalIndent(jsonValue(value), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", bytes)
+		return err
+	}
+
+	encoder := yamlv3.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+
+//
+// Convert maps with non-string keys for JSON.
+//
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, vv := range v {
+			result[k] = jsonValue(vv)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, vv := range v {
+			result[fmt.Sprint(k)] = jsonValue(vv)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, vv := range v {
+			result[i] = jsonValue(vv)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+
+//
+// Format the value in one line of YAML flow style.
+//
+func formatInline(value interface{}) string {
+	var node yamlv3.Node
+	if err := node.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	node.Style = yamlv3.FlowStyle
+	bytes, err := yamlv3.Marshal(&node)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(string(bytes))
+}
@@ -0,0 +1,173 @@
+//
+// main_test.go
+//
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+
+//
+// Test the commands against temporary files.
+//
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	prod := filepath.Join(dir, "prod.yaml")
+	if err := os.WriteFile(base, []byte("server:\n  port: 8080\n  host: a.local\nname: app\n"), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	if err := os.WriteFile(prod, []byte("server:\n  port: 9090\ndebug: true\n"), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	dup := filepath.Join(dir, "dup.yaml")
+	if err := os.WriteFile(dup, []byte("server:\n  port: 8080\n  port: 9090\n"), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	schema := filepath.Join(dir, "schema.yaml")
+	if err := os.WriteFile(schema, []byte("properties:\n  server:\n    properties:\n      port:\n        type: integer\n        maximum: 1000\n"), 0600); err != nil {
+		t.Fatalf("Failed to write schema file: %v", err)
+	}
+	replacer := strings.NewReplacer("{base}", base, "{prod}", prod, "{dup}", dup, "{schema}", schema)
+
+	tests := []struct {
+		name   string
+		args   string
+		status int
+		stdout string
+		stderr string // prefix, since the flag errors are followed by the usage
+	}{
+		{
+			name: "get",
+			args: "get -yaml {base} -yaml {prod} server.port",
+			stdout: "9090\n",
+		},
+		{
+			name: "get map",
+			args: "get -yaml {base} server",
+			stdout: "host: a.local\nport: 8080\n",
+		},
+		{
+			name: "get missing key",
+			args: "get -yaml {base} missing",
+			status: 1,
+			stderr: "Error: Key not found. (key: missing)\n",
+		},
+		{
+			name: "merge json",
+			args: "merge -yaml {base} -yaml {prod} -format json",
+			stdout: "{\n  \"debug\": true,\n  \"name\": \"app\",\n  \"server\": {\n    \"host\": \"a.local\",\n    \"port\": 9090\n  }\n}\n",
+		},
+		{
+			name: "diff",
+			args: "diff {base} {prod}",
+			status: 1,
+			stdout: "+ debug: true\n- name: app\n- server.host: a.local\n~ server.port: 8080 -> 9090\n",
+		},
+		{
+			name: "diff same",
+			args: "diff {base} {base}",
+		},
+		{
+			name: "explain",
+			args: "explain -yaml {base} -yaml {prod} server.port",
+			stdout: "key: server.port\nvalue: 9090\norigin: {prod}:2\nsources:\n  - source: {base}\n    value: 8080\n  - source: {prod}\n    value: 9090\n",
+		},
+		{
+			name: "keys",
+			args: "keys -yaml {base} server",
+			stdout: "server.host\nserver.port\n",
+		},
+		{
+			name: "validate",
+			args: "validate -yaml {base} -yaml {prod}",
+			stdout: "OK\n",
+		},
+		{
+			name: "validate duplicate key",
+			args: "validate -yaml {dup}",
+			status: 1,
+			stdout: "server.port: is defined twice [{dup}:2, {dup}:3]\n",
+			stderr: "Error: Invalid config. (1 problems)\n",
+		},
+		{
+			name: "validate schema",
+			args: "validate -yaml {base} -schema {schema}",
+			status: 1,
+			stdout: "server.port: must be <= 1000 [{base}:2]\n",
+			stderr: "Error: Invalid config. (1 problems)\n",
+		},
+		{
+			name: "encrypt without key",
+			args: "encrypt value",
+			status: 1,
+			stderr: "Error: Need the -key-file option.\n",
+		},
+		{
+			name: "unknown flag",
+			args: "get -unknown",
+			status: 2,
+			stderr: "flag provided but not defined: -unknown\nUsage of yamlcfg get:\n",
+		},
+		{
+			name: "unknown command",
+			args: "unknown",
+			status: 2,
+			stderr: "Error: Unknown command. (command: unknown)\n\n" + usage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(strings.Fields(replacer.Replace(tt.args)), &stdout, &stderr)
+			if status != tt.status {
+				t.Errorf("Unexpected exit status. Expected: %d, actual: %d, stderr: %s\n", tt.status, status, stderr.String())
+			}
+			if want := replacer.Replace(tt.stdout); stdout.String() != want {
+				t.Errorf("Unexpected stdout. Expected:\n%s\nactual:\n%s\n", want, stdout.String())
+			}
+			if want := replacer.Replace(tt.stderr); !strings.HasPrefix(stderr.String(), want) || (want == "") != (stderr.Len() == 0) {
+				t.Errorf("Unexpected stderr. Expected:\n%s\nactual:\n%s\n", want, stderr.String())
+			}
+		})
+	}
+}
+
+
+//
+// Test encrypting a value with a new key, and reading it back.
+//
+func TestRun_Encrypt(t *testing.T) {
+	dir := t.TempDir()
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"encrypt", "-genkey"}, &stdout, &stderr); status != 0 {
+		t.Fatalf("Failed to generate a key. (status: %d, stderr: %s)\n", status, stderr.String())
+	}
+	keyFile := filepath.Join(dir, "config.key")
+	if err := os.WriteFile(keyFile, stdout.Bytes(), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+
+	stdout.Reset()
+	if status := run([]string{"encrypt", "-key-file", keyFile, "s3cret"}, &stdout, &stderr); status != 0 {
+		t.Fatalf("Failed to encrypt. (status: %d, stderr: %s)\n", status, stderr.String())
+	}
+	encrypted := strings.TrimSpace(stdout.String())
+	if !strings.HasPrefix(encrypted, "ENC[AES256_GCM,") || strings.Contains(encrypted, "s3cret") {
+		t.Fatalf("Unexpected encrypted value: %s\n", encrypted)
+	}
+
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("password: \"" + encrypted + "\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	stdout.Reset()
+	if status := run([]string{"get", "-yaml", file, "-key-file", keyFile, "password"}, &stdout, &stderr); status != 0 || stdout.String() != "s3cret\n" {
+		t.Errorf("Failed to read the encrypted value. (status: %d, stdout: %s, stderr: %s)\n", status, stdout.String(), stderr.String())
+	}
+}
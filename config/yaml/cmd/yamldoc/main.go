@@ -0,0 +1,216 @@
+//
+// main.go
+//
+// yamldoc generates a commented sample YAML and a Markdown reference from a config struct.
+//
+//   //go:generate go run github.com/k4k3ru-hub/go/config/yaml/cmd/yamldoc -type Config -sample config.sample.yaml -doc CONFIG.md
+//
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+const (
+	// Test file which writes the fields of the struct type to YAMLDOC_OUTPUT.
+	describeTest = `// Code generated by yamldoc. DO NOT EDIT.
+
+package %s
+
+import (
+	yamldoc_json "encoding/json"
+	yamldoc_os "os"
+	yamldoc_testing "testing"
+
+	yamldoc_yaml "github.com/k4k3ru-hub/go/config/yaml"
+)
+
+func TestYAMLDocDescribe(t *yamldoc_testing.T) {
+	fields, err := yamldoc_yaml.DescribeStruct((*%s)(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := yamldoc_json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := yamldoc_os.WriteFile(yamldoc_os.Getenv("YAMLDOC_OUTPUT"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+`
+)
+
+
+//
+// Main.
+//
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+
+//
+// Run the command, and return the exit status.
+//
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("yamldoc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	typeName := fs.String("type", "", "Name of the config struct type")
+	dir := fs.String("dir", ".", "Directory of the Go package")
+	samplePath := fs.String("sample", "", "Output path of the sample YAML (stdout if both outputs are empty)")
+	docPath := fs.String("doc", "", "Output path of the Markdown reference")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	if *typeName == "" {
+		fmt.Fprintf(stderr, "Error: Need the -type option.\n")
+		return 2
+	}
+
+	fields, err := describe(*dir, *typeName)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	if *samplePath == "" && *docPath == "" {
+		if err := yaml.WriteSample(stdout, fields); err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return 1
+		}
+		return 0
+	}
+	if *samplePath != "" {
+		if err := writeFile(*samplePath, func(f *os.File) error { return yaml.WriteSample(f, fields) }); err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return 1
+		}
+	}
+	if *docPath != "" {
+		if err := writeFile(*docPath, func(f *os.File) error { return yaml.WriteReference(f, fields) }); err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+
+//
+// Describe the struct type declared in the package directory.
+// A test file calling yaml.DescribeStruct is run in the package through a build overlay,
+// so the package may be main and the keys match the ones decoded at run time.
+// Nothing is written to the package directory, and its own test files are left out of the build.
+//
+func describe(dir, typeName string) ([]yaml.FieldDoc, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkgName, err := packageName(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "yamldoc-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	testFile := filepath.Join(tmpDir, "describe_test.go")
+	if err := os.WriteFile(testFile, []byte(fmt.Sprintf(describeTest, pkgName, typeName)), 0600); err != nil {
+		return nil, err
+	}
+	overlay := map[string]string{
+		filepath.Join(dir, "yamldoc_describe_test.go"): testFile,
+	}
+	testPaths, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range testPaths {
+		overlay[path] = ""
+	}
+	data, err := json.Marshal(map[string]interface{}{"Replace": overlay})
+	if err != nil {
+		return nil, err
+	}
+	overlayFile := filepath.Join(tmpDir, "overlay.json")
+	if err := os.WriteFile(overlayFile, data, 0600); err != nil {
+		return nil, err
+	}
+
+	output := filepath.Join(tmpDir, "fields.json")
+	cmd := exec.Command("go", "test", "-count=1", "-vet=off", "-overlay", overlayFile, "-run", "^TestYAMLDocDescribe$", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "YAMLDOC_OUTPUT=" + output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("Failed to describe the struct type. (type: %s, dir: %s, error: %s)\n%s", typeName, dir, err, out)
+	}
+
+	data, err = os.ReadFile(output)
+	if err != nil {
+		return nil, err
+	}
+	var fields []yaml.FieldDoc
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+
+//
+// Get the package name of the Go files in the directory.
+//
+func packageName(dir string) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", err
+		}
+		return file.Name.Name, nil
+	}
+	return "", fmt.Errorf("No Go files found. (dir: %s)", dir)
+}
+
+
+//
+// Create the file and write to it.
+//
+func writeFile(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
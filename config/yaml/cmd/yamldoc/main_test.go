@@ -0,0 +1,96 @@
+//
+// main_test.go
+//
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+
+//
+// Test generating the sample and the reference from the fixture package.
+//
+func TestRun(t *testing.T) {
+	fixture := filepath.Join("testdata", "fixture")
+	before, err := os.ReadDir(fixture)
+	if err != nil {
+		t.Fatalf("Failed to read the fixture: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"-type", "Config", "-dir", fixture}, &stdout, &stderr); status != 0 {
+		t.Fatalf("Failed to run. (status: %d, stderr: %s)\n", status, stderr.String())
+	}
+	for _, s := range []string{"# HTTP server\nserver:", "  # Listen port (int)\n  port: 8080", "  timeout: 30s"} {
+		if !strings.Contains(stdout.String(), s) {
+			t.Errorf("Sample does not contain %q.\n%s", s, stdout.String())
+		}
+	}
+
+	doc := filepath.Join(t.TempDir(), "CONFIG.md")
+	if status := run([]string{"-type", "Config", "-dir", fixture, "-doc", doc}, &stdout, &stderr); status != 0 {
+		t.Fatalf("Failed to run. (status: %d, stderr: %s)\n", status, stderr.String())
+	}
+	if data, err := os.ReadFile(doc); err != nil || !strings.Contains(string(data), "| `server.port` | int | `8080` | Listen port |") {
+		t.Errorf("Reference does not contain server.port. (error: %v)\n%s", err, data)
+	}
+
+	// Nothing is left in the package directory.
+	if after, err := os.ReadDir(fixture); err != nil || len(after) != len(before) {
+		t.Errorf("Unexpected files in the fixture: %v\n", after)
+	}
+}
+
+
+//
+// Test the errors of the command.
+//
+func TestRun_Error(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		status int
+		stderr string
+	}{
+		{
+			name: "missing type",
+			args: []string{"-dir", "testdata/fixture"},
+			status: 2,
+			stderr: "Error: Need the -type option.\n",
+		},
+		{
+			name: "unknown type",
+			args: []string{"-type", "Missing", "-dir", "testdata/fixture"},
+			status: 1,
+			stderr: "Error: Failed to describe the struct type. (type: Missing,",
+		},
+		{
+			name: "no Go files",
+			args: []string{"-type", "Config", "-dir", "testdata"},
+			status: 1,
+			stderr: "Error: No Go files found.",
+		},
+		{
+			name: "unknown flag",
+			args: []string{"-unknown"},
+			status: 2,
+			stderr: "flag provided but not defined: -unknown\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if status := run(tt.args, &stdout, &stderr); status != tt.status {
+				t.Errorf("Unexpected exit status. Expected: %d, actual: %d, stderr: %s\n", tt.status, status, stderr.String())
+			}
+			if !strings.HasPrefix(stderr.String(), tt.stderr) {
+				t.Errorf("Unexpected stderr. Expected prefix:\n%s\nactual:\n%s\n", tt.stderr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+//
+// main.go
+//
+// Fixture package for the yamldoc tests.
+//
+package main
+
+import (
+	"time"
+)
+
+
+type Config struct {
+	Server struct {
+		Port    int           `yaml:"port" default:"8080" desc:"Listen port"`
+		Timeout time.Duration `yaml:"timeout" default:"30s" desc:"Request timeout"`
+	} `yaml:"server" desc:"HTTP server"`
+}
+
+
+func main() {}
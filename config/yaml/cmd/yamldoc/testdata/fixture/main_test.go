@@ -0,0 +1,10 @@
+//
+// main_test.go
+//
+// Does not compile, to check that yamldoc leaves the tests of the package out.
+//
+package main
+
+func TestBroken(t *testing.T) {
+	undefined()
+}
@@ -0,0 +1,179 @@
+//
+// coerce.go
+//
+package yaml
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+
+var (
+	lenient atomic.Bool
+)
+
+
+//
+// Enable the lenient coercion of strings in the getters and Decode.
+// Numeric strings such as "8080" are converted to numbers, and true/false, yes/no, on/off and 1/0 to bools,
+// so values from env variables or quoted in YAML can be read as their types.
+//
+func SetLenient(enabled bool) {
+	lenient.Store(enabled)
+	settingsGen.Add(1)
+}
+
+
+//
+// Convert the value to the bool type.
+//
+func convertBool(v reflect.Value, t reflect.Type) (interface{}, bool) {
+	if v.Kind() == reflect.Bool {
+		return v.Convert(t).Interface(), true
+	}
+	if !lenient.Load() {
+		return nil, false
+	}
+
+	var b bool
+	switch v.Kind() {
+	case reflect.String:
+		switch strings.ToLower(strings.TrimSpace(v.String())) {
+		case "true", "yes", "on", "1":
+			b = true
+		case "false", "no", "off", "0":
+			b = false
+		default:
+			return nil, false
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := v.Int(); n != 0 && n != 1 {
+			return nil, false
+		}
+		b = v.Int() == 1
+	default:
+		return nil, false
+	}
+	return reflect.ValueOf(b).Convert(t).Interface(), true
+}
+
+
+//
+// Convert the value to the numeric type, failing on overflow or on a fraction for an integer type.
+// Strings are parsed only in the lenient mode.
+//
+func convertNumber(value interface{}, t reflect.Type) (interface{}, bool, error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.String {
+		if !lenient.Load() {
+			return nil, false, nil
+		}
+		parsed, err := parseNumber(strings.TrimSpace(v.String()), t)
+		if err != nil {
+			return nil, true, err
+		}
+		v = reflect.ValueOf(parsed)
+	}
+	if !isNumberKind(v.Kind()) {
+		return nil, false, nil
+	}
+
+	result := reflect.New(t).Elem()
+	outOfRange := func() error {
+		return fmt.Errorf("Failed to convert %v to %s: out of range.", value, t)
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = v.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if v.Uint() > math.MaxInt64 {
+				return nil, true, outOfRange()
+			}
+			n = int64(v.Uint())
+		default:
+			f := v.Float()
+			if f != math.Trunc(f) {
+				return nil, true, fmt.Errorf("Failed to convert %v to %s: not an integer.", value, t)
+			}
+			if f < math.MinInt64 || f >= math.MaxInt64 {
+				return nil, true, outOfRange()
+			}
+			n = int64(f)
+		}
+		if result.OverflowInt(n) {
+			return nil, true, outOfRange()
+		}
+		result.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if v.Int() < 0 {
+				return nil, true, outOfRange()
+			}
+			n = uint64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n = v.Uint()
+		default:
+			f := v.Float()
+			if f != math.Trunc(f) {
+				return nil, true, fmt.Errorf("Failed to convert %v to %s: not an integer.", value, t)
+			}
+			if f < 0 || f >= math.MaxUint64 {
+				return nil, true, outOfRange()
+			}
+			n = uint64(f)
+		}
+		if result.OverflowUint(n) {
+			return nil, true, outOfRange()
+		}
+		result.SetUint(n)
+	default:
+		f := v.Convert(reflect.TypeOf(float64(0))).Float()
+		if result.OverflowFloat(f) {
+			return nil, true, outOfRange()
+		}
+		result.SetFloat(f)
+	}
+	return result.Interface(), true, nil
+}
+
+
+//
+// Parse the numeric string for the type.
+// Integers are decimal unless they have the 0x, 0o or 0b prefix, so "010" is 10.
+//
+func parseNumber(s string, t reflect.Type) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to convert %q to %s.", s, t)
+		}
+		return f, nil
+	default:
+		base := 10
+		if lower := strings.ToLower(strings.TrimLeft(s, "+-")); strings.HasPrefix(lower, "0x") || strings.HasPrefix(lower, "0o") || strings.HasPrefix(lower, "0b") {
+			base = 0
+		}
+		if n, err := strconv.ParseInt(s, base, 64); err == nil {
+			return n, nil
+		}
+		if n, err := strconv.ParseUint(s, base, 64); err == nil {
+			return n, nil
+		}
+		// Integral floats such as "1e3".
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, nil
+		}
+		return nil, fmt.Errorf("Failed to convert %q to %s.", s, t)
+	}
+}
@@ -0,0 +1,91 @@
+//
+// coerce_test.go
+//
+package yaml_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test overflow-checked numeric conversions.
+//
+func TestGet_Overflow(t *testing.T) {
+	store := yaml.NewStore(map[string]interface{}{
+		"big":      uint64(math.MaxUint64),
+		"ratio":    1.5,
+		"count":    3.0,
+		"negative": -1,
+		"port":     70000,
+	})
+
+	if _, err := yaml.GetFrom[int64](store, "big"); err == nil {
+		t.Errorf("Expected an overflow error for int64.\n")
+	}
+	if v, err := yaml.GetFrom[uint64](store, "big"); err != nil || v != math.MaxUint64 {
+		t.Errorf("Failed to get big as uint64. (value: %d, error: %v)\n", v, err)
+	}
+	if v := store.GetInt("ratio"); v != 0 {
+		t.Errorf("Expected 0 for a fraction, actual: %d\n", v)
+	}
+	if v := store.GetInt("count"); v != 3 {
+		t.Errorf("Expected 3 for an integral float, actual: %d\n", v)
+	}
+	if _, err := yaml.GetFrom[uint](store, "negative"); err == nil {
+		t.Errorf("Expected an error for a negative uint.\n")
+	}
+	if _, err := yaml.GetFrom[uint16](store, "port"); err == nil {
+		t.Errorf("Expected an overflow error for uint16.\n")
+	}
+}
+
+
+//
+// Test the lenient coercion of strings.
+//
+func TestSetLenient(t *testing.T) {
+	config := map[string]interface{}{
+		"port":    "8080",
+		"ratio":   " 0.25 ",
+		"mode":    "010",
+		"mask":    "0xff",
+		"enabled": "Yes",
+		"debug":   "off",
+		"flag":    1,
+		"name":    "api",
+	}
+
+	strict := yaml.NewStore(config)
+	if v := strict.GetInt("port"); v != 0 {
+		t.Errorf("Expected 0 without the lenient mode, actual: %d\n", v)
+	}
+
+	yaml.SetLenient(true)
+	defer yaml.SetLenient(false)
+	store := yaml.NewStore(config)
+	if v := store.GetInt("port"); v != 8080 {
+		t.Errorf("Expected 8080, actual: %d\n", v)
+	}
+	if v := store.GetFloat64("ratio"); v != 0.25 {
+		t.Errorf("Expected 0.25, actual: %v\n", v)
+	}
+	if v := store.GetInt("mode"); v != 10 {
+		t.Errorf("Expected 10 for a decimal string, actual: %d\n", v)
+	}
+	if v := store.GetInt64("mask"); v != 255 {
+		t.Errorf("Expected 255, actual: %d\n", v)
+	}
+	if !store.GetBool("enabled") || store.GetBool("debug") || !store.GetBool("flag") {
+		t.Errorf("Unexpected bools. (enabled: %v, debug: %v, flag: %v)\n", store.GetBool("enabled"), store.GetBool("debug"), store.GetBool("flag"))
+	}
+	if _, err := yaml.GetFrom[int](store, "name"); err == nil {
+		t.Errorf("Expected an error for a non-numeric string.\n")
+	}
+	if _, err := yaml.GetFrom[int8](store, "port"); err == nil {
+		t.Errorf("Expected an overflow error for int8.\n")
+	}
+}
@@ -0,0 +1,380 @@
+//
+// convert.go
+//
+package yaml
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+
+var (
+	ErrKeyNotFound = errors.New("Key not found.")
+
+	convertersMu sync.RWMutex
+	converters   = map[reflect.Type]func(value interface{}) (interface{}, error){
+		reflect.TypeOf(time.Duration(0)): convertDuration,
+		reflect.TypeOf((*time.Location)(nil)): convertLocation,
+		reflect.TypeOf(os.FileMode(0)): convertFileMode,
+		reflect.TypeOf(net.IPNet{}): func(value interface{}) (interface{}, error) {
+			n, err := convertIPNet(value)
+			if err != nil {
+				return nil, err
+			}
+			return *n, nil
+		},
+		reflect.TypeOf((*net.IPNet)(nil)): func(value interface{}) (interface{}, error) { return convertIPNet(value) },
+		reflect.TypeOf(url.URL{}): func(value interface{}) (interface{}, error) {
+			u, err := convertURL(value)
+			if err != nil {
+				return nil, err
+			}
+			return *u, nil
+		},
+		reflect.TypeOf((*url.URL)(nil)): func(value interface{}) (interface{}, error) { return convertURL(value) },
+		reflect.TypeOf((*regexp.Regexp)(nil)): func(value interface{}) (interface{}, error) { return convertRegexp(value) },
+	}
+	decodeHooks []DecodeHook
+
+	intType             = reflect.TypeOf(0)
+	stringType          = reflect.TypeOf("")
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+)
+
+
+//
+// DecodeHook converts a config value to the type t.
+// It returns false to leave the value to the next hook or the built-in conversions.
+//
+type DecodeHook func(value interface{}, t reflect.Type) (interface{}, bool, error)
+
+
+//
+// Register a converter from a config value to T.
+// Registered converters take precedence over the built-in conversions.
+//
+func RegisterConverter[T any](converter func(value interface{}) (T, error)) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters[reflect.TypeOf((*T)(nil)).Elem()] = func(value interface{}) (interface{}, error) {
+		return converter(value)
+	}
+	settingsGen.Add(1)
+}
+
+
+//
+// Register a decode hook used by Get and Decode for any target type.
+// Hooks are called in the registered order after the converters registered for the exact type.
+//
+func RegisterDecodeHook(hook DecodeHook) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	decodeHooks = append(decodeHooks, hook)
+	settingsGen.Add(1)
+}
+
+
+//
+// Get the value of the key converted to T.
+//
+func Get[T any](key string) (T, error) {
+	return GetFrom[T](std, key)
+}
+
+
+//
+// Get the value of the key in the store converted to T.
+//
+func GetFrom[T any](s *Store, key string) (T, error) {
+	return getAs[T](s, key, nil)
+}
+
+
+//
+// Convert the config value to the type.
+// Maps and lists returned as they are, such as for interface{} targets, are copied from the config.
+//
+func convertTo(value interface{}, t reflect.Type) (interface{}, error) {
+	if value == nil {
+		return nil, fmt.Errorf("Failed to convert null to %s.", t)
+	}
+	if result, ok, err := convertCustom(value, t); ok {
+		return result, err
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type() == t || (t.Kind() == reflect.Interface && v.Type().Implements(t)) {
+		return copyValue(value), nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if s, ok := formatScalar(v); ok {
+			return reflect.ValueOf(s).Convert(t).Interface(), nil
+		}
+	case reflect.Bool:
+		if result, ok := convertBool(v, t); ok {
+			return result, nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if result, ok, err := convertNumber(value, t); ok {
+			return result, err
+		}
+	case reflect.Slice:
+		if items, ok := value.([]interface{}); ok {
+			result := reflect.MakeSlice(t, len(items), len(items))
+			for i, item := range items {
+				converted, err := convertTo(item, t.Elem())
+				if err != nil {
+					return nil, err
+				}
+				result.Index(i).Set(reflect.ValueOf(converted))
+			}
+			return result.Interface(), nil
+		}
+	case reflect.Map:
+		if m, ok := value.(map[string]interface{}); ok && t.Key().Kind() == reflect.String {
+			result := reflect.MakeMapWithSize(t, len(m))
+			for k, item := range m {
+				converted, err := convertTo(item, t.Elem())
+				if err != nil {
+					return nil, err
+				}
+				result.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), reflect.ValueOf(converted))
+			}
+			return result.Interface(), nil
+		}
+	}
+	return nil, fmt.Errorf("Failed to convert %T to %s.", value, t)
+}
+
+
+//
+// Convert the config value by the registered converters and hooks, yaml.Unmarshaler or encoding.TextUnmarshaler.
+// It returns false if none of them applies to the type.
+//
+func convertCustom(value interface{}, t reflect.Type) (interface{}, bool, error) {
+	convertersMu.RLock()
+	converter, ok := converters[t]
+	hooks := decodeHooks
+	convertersMu.RUnlock()
+	if ok {
+		result, err := converter(value)
+		return result, true, err
+	}
+	for _, hook := range hooks {
+		if result, ok, err := hook(value, t); ok {
+			return result, true, err
+		}
+	}
+
+	// yaml.Unmarshaler, given the config value as a node.
+	if implementsPointer(t, yamlUnmarshalerType) {
+		var node yaml.Node
+		if err := node.Encode(value); err != nil {
+			return nil, true, err
+		}
+		result, err := unmarshalPointer(t, func(u interface{}) error {
+			return u.(yaml.Unmarshaler).UnmarshalYAML(&node)
+		})
+		return result, true, err
+	}
+
+	// encoding.TextUnmarshaler.
+	if text, ok := value.(string); ok && implementsPointer(t, textUnmarshalerType) {
+		result, err := unmarshalPointer(t, func(u interface{}) error {
+			return u.(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+		})
+		return result, true, err
+	}
+	return nil, false, nil
+}
+
+
+//
+// Check if the type, or the pointer to it, is converted by a registered converter,
+// yaml.Unmarshaler or encoding.TextUnmarshaler.
+//
+func hasCustomConverter(t reflect.Type) bool {
+	convertersMu.RLock()
+	_, ok := converters[t]
+	if !ok {
+		_, ok = converters[reflect.PointerTo(t)]
+	}
+	convertersMu.RUnlock()
+	return ok || implementsPointer(t, yamlUnmarshalerType) || implementsPointer(t, textUnmarshalerType)
+}
+
+
+//
+// Check if the pointer type, or the pointer to the type, implements the interface.
+//
+func implementsPointer(t, iface reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		return t.Implements(iface)
+	}
+	return reflect.PointerTo(t).Implements(iface)
+}
+
+
+//
+// Make a new value of the type, and unmarshal it through its pointer.
+//
+func unmarshalPointer(t reflect.Type, unmarshal func(u interface{}) error) (interface{}, error) {
+	if t.Kind() == reflect.Pointer {
+		result := reflect.New(t.Elem())
+		if err := unmarshal(result.Interface()); err != nil {
+			return nil, err
+		}
+		return result.Interface(), nil
+	}
+	result := reflect.New(t)
+	if err := unmarshal(result.Interface()); err != nil {
+		return nil, err
+	}
+	return result.Elem().Interface(), nil
+}
+
+
+//
+// Format a bool or number as a string.
+//
+func formatScalar(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+
+//
+// Check if the kind is an int, uint or float.
+//
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+
+//
+// Convert a duration string such as "1m30s".
+//
+func convertDuration(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return time.ParseDuration(v)
+	case time.Duration:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("Failed to convert %T to time.Duration.", value)
+	}
+}
+
+
+//
+// Convert a location name such as "Asia/Tokyo".
+//
+func convertLocation(value interface{}) (interface{}, error) {
+	name, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("Failed to convert %T to *time.Location.", value)
+	}
+	return time.LoadLocation(name)
+}
+
+
+//
+// Convert a file mode such as "0644", "0o755" or an int.
+// Strings are always parsed as octal.
+//
+func convertFileMode(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		mode, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimPrefix(v, "0o"), "0O"), 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to convert %q to os.FileMode.", v)
+		}
+		return os.FileMode(mode), nil
+	case int:
+		if v < 0 {
+			return nil, fmt.Errorf("Failed to convert %d to os.FileMode.", v)
+		}
+		return os.FileMode(v), nil
+	case os.FileMode:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("Failed to convert %T to os.FileMode.", value)
+	}
+}
+
+
+//
+// Convert a CIDR such as "10.0.0.0/8".
+//
+func convertIPNet(value interface{}) (*net.IPNet, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("Failed to convert %T to net.IPNet.", value)
+	}
+	_, n, err := net.ParseCIDR(s)
+	return n, err
+}
+
+
+//
+// Convert a URL such as "https://example.com/api".
+//
+func convertURL(value interface{}) (*url.URL, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("Failed to convert %T to url.URL.", value)
+	}
+	return url.Parse(s)
+}
+
+
+//
+// Convert a regular expression.
+//
+func convertRegexp(value interface{}) (*regexp.Regexp, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("Failed to convert %T to regexp.Regexp.", value)
+	}
+	return regexp.Compile(s)
+}
@@ -0,0 +1,108 @@
+//
+// convert_test.go
+//
+package yaml_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+type testLevel int
+
+
+//
+// Test Get with built-in conversions.
+//
+func TestGet(t *testing.T) {
+	store := yaml.NewStore(map[string]interface{}{
+		"port":    8080,
+		"ratio":   0.5,
+		"timeout": "1m30s",
+		"ip":      "192.0.2.1",
+		"pattern": "^a+$",
+		"hosts":   []interface{}{"a", "b"},
+	})
+
+	if v, err := yaml.GetFrom[int64](store, "port"); err != nil || v != 8080 {
+		t.Errorf("Failed to get port. Expected: 8080, actual: %d, error: %v\n", v, err)
+	}
+	if v, err := yaml.GetFrom[string](store, "ratio"); err != nil || v != "0.5" {
+		t.Errorf("Failed to get ratio. Expected: 0.5, actual: %s, error: %v\n", v, err)
+	}
+	if v, err := yaml.GetFrom[time.Duration](store, "timeout"); err != nil || v != 90*time.Second {
+		t.Errorf("Failed to get timeout. Expected: 1m30s, actual: %s, error: %v\n", v, err)
+	}
+	if v, err := yaml.GetFrom[net.IP](store, "ip"); err != nil || v.String() != "192.0.2.1" {
+		t.Errorf("Failed to get ip. Expected: 192.0.2.1, actual: %s, error: %v\n", v, err)
+	}
+	if v, err := yaml.GetFrom[*regexp.Regexp](store, "pattern"); err != nil || !v.MatchString("aaa") {
+		t.Errorf("Failed to get pattern. Actual: %v, error: %v\n", v, err)
+	}
+	if v, err := yaml.GetFrom[[]string](store, "hosts"); err != nil || len(v) != 2 || v[1] != "b" {
+		t.Errorf("Failed to get hosts. Expected: [a b], actual: %v, error: %v\n", v, err)
+	}
+	if _, err := yaml.GetFrom[bool](store, "port"); err == nil {
+		t.Errorf("Expected an error for converting int to bool.\n")
+	}
+	if _, err := yaml.GetFrom[int](store, "missing"); !errors.Is(err, yaml.ErrKeyNotFound) {
+		t.Errorf("Expected ErrKeyNotFound, actual: %v\n", err)
+	}
+}
+
+
+//
+// Test Get with a registered converter.
+//
+func TestGet_RegisteredConverter(t *testing.T) {
+	yaml.RegisterConverter(func(value interface{}) (testLevel, error) {
+		switch value {
+		case "debug":
+			return 0, nil
+		case "info":
+			return 1, nil
+		default:
+			return 0, fmt.Errorf("Unknown level: %v", value)
+		}
+	})
+	store := yaml.NewStore(map[string]interface{}{"level": "info", "bad": "trace"})
+
+	if v, err := yaml.GetFrom[testLevel](store, "level"); err != nil || v != 1 {
+		t.Errorf("Failed to get level. Expected: 1, actual: %d, error: %v\n", v, err)
+	}
+	if _, err := yaml.GetFrom[testLevel](store, "bad"); err == nil {
+		t.Errorf("Expected an error for an unknown level.\n")
+	}
+}
+
+
+//
+// Test lists with null items.
+//
+func TestGet_NullItems(t *testing.T) {
+	store := yaml.NewStore(map[string]interface{}{
+		"hosts": []interface{}{"a", nil, "b"},
+		"ports": []interface{}{1, nil, 2},
+		"names": map[string]interface{}{"a": nil},
+	})
+
+	if v := store.GetArrayString("hosts"); len(v) != 2 || v[0] != "a" || v[1] != "b" {
+		t.Errorf("Expected [a b], actual: %v\n", v)
+	}
+	if v := store.GetArrayInt("ports"); len(v) != 2 || v[0] != 1 || v[1] != 2 {
+		t.Errorf("Expected [1 2], actual: %v\n", v)
+	}
+	if _, err := yaml.GetFrom[[]string](store, "hosts"); err == nil {
+		t.Errorf("Expected an error for a null item.\n")
+	}
+	if _, err := yaml.GetFrom[map[string]string](store, "names"); err == nil {
+		t.Errorf("Expected an error for a null value.\n")
+	}
+}
@@ -0,0 +1,214 @@
+//
+// debug.go
+//
+package yaml
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+
+const (
+	RedactedValue = "[REDACTED]"
+)
+
+
+var (
+	// Values of the keys containing any of them (case-insensitive) are redacted by DebugHandler.
+	DefaultRedactKeys = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "private_key", "credential"}
+)
+
+
+//
+// DebugHandler serves the current config of a store for operators.
+//
+//   GET  /debug/config              JSON of the redacted config, origins, version and load time
+//   GET  /debug/config?format=yaml  the same in YAML
+//   GET  /debug/config?key=server   only the keys under server
+//   POST /debug/config              reload, with "Authorization: Bearer <Token>"
+//
+// Reloading is disabled if Token is empty.
+//
+type DebugHandler struct {
+	Store      *Store
+	Token      string
+	RedactKeys []string
+}
+
+
+//
+// debugResponse is the document served by DebugHandler.
+//
+type debugResponse struct {
+	Version  uint64            `json:"version" yaml:"version"`
+	LoadedAt time.Time         `json:"loaded_at" yaml:"loaded_at"`
+	Digests  map[string]string `json:"digests,omitempty" yaml:"digests,omitempty"`
+	Config   interface{}       `json:"config" yaml:"config"`
+	Origins  map[string]string `json:"origins" yaml:"origins"`
+}
+
+
+//
+// New DebugHandler for the store.
+// A nil store serves the default store.
+//
+func NewDebugHandler(store *Store) *DebugHandler {
+	if store == nil {
+		store = std
+	}
+	return &DebugHandler{
+		Store: store,
+		RedactKeys: DefaultRedactKeys,
+	}
+}
+
+
+//
+// Serve the config, or reload it. (http.Handler interface)
+//
+func (h *DebugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.serveConfig(w, r)
+	case http.MethodPost:
+		h.serveReload(w, r)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+	}
+}
+
+
+//
+// Serve the redacted config with its origins, version and load time.
+//
+func (h *DebugHandler) serveConfig(w http.ResponseWriter, r *http.Request) {
+	key := getNormalizeOption().normalizeKey(r.URL.Query().Get("key"))
+	metadata := h.Store.Metadata()
+	response := &debugResponse{
+		Version: h.Store.Version(),
+		LoadedAt: metadata.LoadedAt,
+		Digests: metadata.Digests,
+		Origins: make(map[string]string),
+	}
+
+	h.Store.mu.RLock()
+	var config interface{} = *h.Store.config
+	if key != "" {
+		config = lookupPath(*h.Store.config, strings.Split(key, "."))
+	}
+	response.Config = h.redact(config, key)
+	for k, origin := range h.Store.origins {
+		if key == "" || k == key || strings.HasPrefix(k, key + ".") {
+			response.Origins[k] = origin.String()
+		}
+	}
+	h.Store.mu.RUnlock()
+
+	if config == nil && key != "" {
+		http.Error(w, fmt.Sprintf("Key not found. (key: %s)", key), http.StatusNotFound)
+		return
+	}
+
+	var body []byte
+	var err error
+	if r.URL.Query().Get("format") == "yaml" || strings.Contains(r.Header.Get("Accept"), "yaml") {
+		w.Header().Set("Content-Type", "application/yaml")
+		body, err = yaml.Marshal(response)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		body, err = json.MarshalIndent(response, "", "  ")
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode config. (error: %s)", err), http.StatusInternalServerError)
+		return
+	}
+	w.Write(body)
+}
+
+
+//
+// Reload the store if the request has the token.
+//
+func (h *DebugHandler) serveReload(w http.ResponseWriter, r *http.Request) {
+	if h.Token == "" {
+		http.Error(w, "Reload is disabled.", http.StatusForbidden)
+		return
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) != 1 {
+		http.Error(w, "Invalid token.", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.Store.Reload(); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to reload config. (error: %s)", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]uint64{"version": h.Store.Version()})
+}
+
+
+//
+// Copy the value with the values of the sensitive keys redacted.
+// Decrypted values and values derived from references to secrets are always redacted.
+// Maps with non-string keys are converted to string keys for JSON.
+//
+func (h *DebugHandler) redact(value interface{}, key string) interface{} {
+	if key != "" && (h.Store.secrets[key] || h.isSensitive(key[strings.LastIndex(key, ".")+1:])) {
+		return RedactedValue
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, vv := range v {
+			result[k] = h.redact(vv, joinKey(key, k))
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, vv := range v {
+			result[fmt.Sprint(k)] = h.redact(vv, joinKey(key, fmt.Sprint(k)))
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, vv := range v {
+			result[i] = h.redact(vv, joinKey(key, strconv.Itoa(i)))
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+
+//
+// Check if the key name contains any of the redacted keys.
+//
+func (h *DebugHandler) isSensitive(name string) bool {
+	return isSensitiveName(name, h.RedactKeys)
+}
+
+
+//
+// Check if the key name contains any of the redact keys.
+//
+func isSensitiveName(name string, redactKeys []string) bool {
+	name = strings.ToLower(name)
+	for _, redactKey := range redactKeys {
+		if strings.Contains(name, strings.ToLower(redactKey)) {
+			return true
+		}
+	}
+	return false
+}
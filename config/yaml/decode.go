@@ -0,0 +1,150 @@
+//
+// decode.go
+//
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+
+//
+// Decode the config under the key into the struct, and validate it.
+// An empty key decodes the whole config.
+//
+func Decode(key string, out interface{}) error {
+	return std.Decode(key, out)
+}
+
+
+//
+// Decode the config under the key into the struct, and validate it.
+// An empty key decodes the whole config.
+// Fields are decoded by the converters and decode hooks, yaml.Unmarshaler or encoding.TextUnmarshaler before the yaml tags.
+//
+func (s *Store) Decode(key string, out interface{}) error {
+	var v interface{}
+	if key == "" {
+		v = s.Config()
+	} else {
+		v = s.getInterfaceValue(key)
+	}
+
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("Need a non-nil pointer to decode. (type: %T)", out)
+	}
+	if err := decodeValue(v, rv.Elem(), key); err != nil {
+		return err
+	}
+	return validateDecoded(out, v, key)
+}
+
+
+//
+// Decode the config value into the settable value.
+// Nil values leave the destination unchanged.
+//
+func decodeValue(value interface{}, out reflect.Value, key string) error {
+	if value == nil {
+		return nil
+	}
+
+	t := out.Type()
+	if result, ok, err := convertCustom(value, t); ok {
+		if err != nil {
+			return fmt.Errorf("Failed to decode. (key: %s, error: %s)", key, err)
+		}
+		out.Set(reflect.ValueOf(result))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if out.IsNil() {
+			out.Set(reflect.New(t.Elem()))
+		}
+		return decodeValue(value, out.Elem(), key)
+	case reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			break
+		}
+		return decodeStruct(m, out, key)
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			break
+		}
+		result := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, result.Index(i), joinKey(key, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		out.Set(result)
+		return nil
+	case reflect.Map:
+		m, ok := value.(map[string]interface{})
+		if !ok || t.Key().Kind() != reflect.String {
+			break
+		}
+		if out.IsNil() {
+			out.Set(reflect.MakeMapWithSize(t, len(m)))
+		}
+		for k, item := range m {
+			elem := reflect.New(t.Elem()).Elem()
+			if err := decodeValue(item, elem, joinKey(key, k)); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), elem)
+		}
+		return nil
+	}
+
+	result, err := convertTo(value, t)
+	if err != nil {
+		return fmt.Errorf("Failed to decode. (key: %s, error: %s)", key, err)
+	}
+	out.Set(reflect.ValueOf(result))
+	return nil
+}
+
+
+//
+// Decode the map into the fields of the struct by their yaml tags.
+// Unknown keys are ignored.
+//
+func decodeStruct(m map[string]interface{}, out reflect.Value, key string) error {
+	t := out.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, inline := fieldKey(field)
+		if name == "-" || (!field.IsExported() && !(field.Anonymous && inline)) {
+			continue
+		}
+		if inline {
+			if err := decodeValue(m, out.Field(i), key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := decodeValue(lookupField(m, name), out.Field(i), joinKey(key, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+
+//
+// Get the value of the field name in the map, or its normalized name.
+//
+func lookupField(m map[string]interface{}, name string) interface{} {
+	if value, ok := m[name]; ok {
+		return value
+	}
+	return m[getNormalizeOption().normalize(name)]
+}
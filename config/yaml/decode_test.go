@@ -0,0 +1,166 @@
+//
+// decode_test.go
+//
+package yaml_test
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	config "github.com/k4k3ru-hub/go/config/yaml"
+	"github.com/k4k3ru-hub/go/config/yaml/yamltest"
+)
+
+
+type testColor int
+
+type testPoint struct {
+	X, Y int
+}
+
+type testLimits struct {
+	Burst int `yaml:"burst"`
+}
+
+type testDomainConfig struct {
+	Network  net.IPNet         `yaml:"network"`
+	Trusted  []*net.IPNet      `yaml:"trusted"`
+	Endpoint *url.URL          `yaml:"endpoint"`
+	Zone     *time.Location    `yaml:"zone"`
+	Mode     os.FileMode       `yaml:"mode"`
+	DirMode  os.FileMode       `yaml:"dir_mode"`
+	Pattern  *regexp.Regexp    `yaml:"pattern"`
+	Color    testColor         `yaml:"color"`
+	Origin   testPoint         `yaml:"origin"`
+	Timeouts map[string]time.Duration `yaml:"timeouts"`
+	testLimits `yaml:",inline"`
+}
+
+
+//
+// Decode a point from "x,y". (yaml.Unmarshaler)
+//
+func (p *testPoint) UnmarshalYAML(node *yaml.Node) error {
+	_, err := fmt.Sscanf(node.Value, "%d,%d", &p.X, &p.Y)
+	return err
+}
+
+
+//
+// Test Decode with the built-in domain types, yaml.Unmarshaler and a decode hook.
+//
+func TestDecode_DomainTypes(t *testing.T) {
+	config.RegisterDecodeHook(func(value interface{}, t reflect.Type) (interface{}, bool, error) {
+		if t != reflect.TypeOf(testColor(0)) {
+			return nil, false, nil
+		}
+		switch strings.ToLower(fmt.Sprint(value)) {
+		case "red":
+			return testColor(1), true, nil
+		case "blue":
+			return testColor(2), true, nil
+		}
+		return nil, true, fmt.Errorf("Unknown color. (value: %v)", value)
+	})
+
+	store := yamltest.NewString(t, `
+app:
+  network: 10.0.0.0/8
+  trusted: [192.168.0.0/16, 172.16.0.0/12]
+  endpoint: https://example.com/api
+  zone: Asia/Tokyo
+  mode: "0640"
+  dir_mode: 0755
+  pattern: ^v[0-9]+$
+  color: Blue
+  origin: "3,4"
+  timeouts:
+    read: 5s
+  burst: 10
+`)
+
+	var c testDomainConfig
+	if err := store.Decode("app", &c); err != nil {
+		t.Fatalf("Failed to decode. Error: %v\n", err)
+	}
+	if c.Network.String() != "10.0.0.0/8" || len(c.Trusted) != 2 || c.Trusted[1].String() != "172.16.0.0/12" {
+		t.Errorf("Unexpected networks. (network: %s, trusted: %v)\n", &c.Network, c.Trusted)
+	}
+	if c.Endpoint == nil || c.Endpoint.Host != "example.com" {
+		t.Errorf("Unexpected endpoint: %v\n", c.Endpoint)
+	}
+	if c.Zone == nil || c.Zone.String() != "Asia/Tokyo" {
+		t.Errorf("Unexpected zone: %v\n", c.Zone)
+	}
+	if c.Mode != 0640 || c.DirMode != 0755 {
+		t.Errorf("Unexpected modes. (mode: %s, dir_mode: %s)\n", c.Mode, c.DirMode)
+	}
+	if c.Pattern == nil || !c.Pattern.MatchString("v12") {
+		t.Errorf("Unexpected pattern: %v\n", c.Pattern)
+	}
+	if c.Color != 2 || c.Origin != (testPoint{3, 4}) || c.Timeouts["read"] != 5*time.Second || c.Burst != 10 {
+		t.Errorf("Unexpected values: %+v\n", c)
+	}
+}
+
+
+//
+// Test Decode reporting the key of a value which fails to convert.
+//
+func TestDecode_Error(t *testing.T) {
+	store := yamltest.NewString(t, `
+app:
+  trusted: [192.168.0.0/16, not-a-cidr]
+`)
+
+	var c testDomainConfig
+	err := store.Decode("app", &c)
+	if err == nil || !strings.Contains(err.Error(), "app.trusted.1") {
+		t.Errorf("Expected an error for app.trusted.1, actual: %v\n", err)
+	}
+}
+
+
+//
+// Test Decode into interface{} values copying the config, so changes do not leak into the store.
+//
+func TestDecode_Interface(t *testing.T) {
+	store := yamltest.NewString(t, `
+app:
+  limits:
+    burst: 10
+  hosts: [a, b]
+`)
+
+	var raw interface{}
+	if err := store.Decode("app", &raw); err != nil {
+		t.Fatalf("Failed to decode. Error: %v\n", err)
+	}
+	raw.(map[string]interface{})["limits"].(map[string]interface{})["burst"] = 20
+
+	var fields struct {
+		Limits interface{}   `yaml:"limits"`
+		Hosts  []interface{} `yaml:"hosts"`
+	}
+	if err := store.Decode("app", &fields); err != nil {
+		t.Fatalf("Failed to decode. Error: %v\n", err)
+	}
+	fields.Limits.(map[string]interface{})["burst"] = 30
+	fields.Hosts[0] = "c"
+
+	if val := store.GetInt("app.limits.burst"); val != 10 {
+		t.Errorf("Decoded value modified the store. Expected: 10, actual: %d\n", val)
+	}
+	if val := store.GetArrayString("app.hosts"); !reflect.DeepEqual(val, []string{"a", "b"}) {
+		t.Errorf("Decoded value modified the store. Expected: [a b], actual: %v\n", val)
+	}
+}
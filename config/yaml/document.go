@@ -0,0 +1,75 @@
+//
+// document.go
+//
+package yaml
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+
+const (
+	DefaultDocumentKey = "profile"
+)
+
+
+var (
+	documentMu     sync.RWMutex
+	documentKey    string
+	documentValues map[string]struct{}
+)
+
+
+//
+// Select the documents of multi-document files by the value of the key (e.g. "profile" or "kind").
+// Documents without the key are always loaded, and documents whose value is not in the values are skipped.
+// Calling it without values loads all documents again.
+//
+func SelectDocuments(key string, values ...string) {
+	documentMu.Lock()
+	defer documentMu.Unlock()
+	if len(values) == 0 {
+		documentKey = ""
+		documentValues = nil
+		return
+	}
+	documentKey = key
+	documentValues = make(map[string]struct{}, len(values))
+	for _, v := range values {
+		documentValues[v] = struct{}{}
+	}
+}
+
+
+//
+// Check if the document is selected.
+//
+func selectDocument(config map[string]interface{}) bool {
+	documentMu.RLock()
+	defer documentMu.RUnlock()
+	if documentKey == "" {
+		return true
+	}
+	v, ok := config[documentKey]
+	if !ok {
+		return true
+	}
+	_, ok = documentValues[fmt.Sprint(v)]
+	return ok
+}
+
+
+//
+// Parse the comma separated values of the -profile option.
+//
+func parseProfiles(s string) []string {
+	var result []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+//
+// document_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test loading all documents of a multi-document file, and selecting them by profile.
+//
+func TestFileSource_MultiDocument(t *testing.T) {
+	file, err := createTempYAMLFile(`key1: base
+key2: base
+---
+profile: dev
+key1: dev
+---
+profile: prod
+key1: prod
+---
+key3: last
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	if val := store.GetString("key1"); val != "prod" {
+		t.Errorf("Failed to read key1 value. Expected: prod, actual: %s\n", val)
+	}
+	if val := store.GetString("key3"); val != "last" {
+		t.Errorf("Failed to read key3 value. Expected: last, actual: %s\n", val)
+	}
+
+	yaml.SelectDocuments("profile", "dev")
+	defer yaml.SelectDocuments("profile")
+
+	store = yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	if val := store.GetString("key1"); val != "dev" {
+		t.Errorf("Failed to read key1 value. Expected: dev, actual: %s\n", val)
+	}
+	if val := store.GetString("key2"); val != "base" {
+		t.Errorf("Failed to read key2 value. Expected: base, actual: %s\n", val)
+	}
+}
@@ -0,0 +1,165 @@
+//
+// encrypt.go
+//
+package yaml
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+
+const (
+	EncryptionKeySize = 32
+
+	encryptedPrefix = "ENC[AES256_GCM,"
+	encryptedSuffix = "]"
+)
+
+
+var (
+	decryptionMu  sync.RWMutex
+	decryptionKey []byte
+)
+
+
+//
+// Encrypt the value with the AES-256-GCM key.
+// The result is "ENC[AES256_GCM,<base64 of nonce and ciphertext>]", which can be written to a config file as a string.
+//
+func Encrypt(plaintext string, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed) + encryptedSuffix, nil
+}
+
+
+//
+// Set the key to decrypt the encrypted values after loading.
+// Loading fails if the config has encrypted values and no key is set.
+//
+func SetDecryptionKey(key []byte) error {
+	if key != nil && len(key) != EncryptionKeySize {
+		return fmt.Errorf("Need a %d byte encryption key. (size: %d)", EncryptionKeySize, len(key))
+	}
+	decryptionMu.Lock()
+	defer decryptionMu.Unlock()
+	decryptionKey = key
+	return nil
+}
+
+
+//
+// Read a base64 encoded encryption key from the file.
+//
+func ReadEncryptionKey(path string) ([]byte, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(bytes)))
+	if err != nil || len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("Need a base64 encoded %d byte key. (path: %s)", EncryptionKeySize, path)
+	}
+	return key, nil
+}
+
+
+//
+// Decrypt the encrypted values of the config in place, and record their keys in the secrets.
+//
+func decryptValues(config map[string]interface{}, secrets map[string]bool) error {
+	decryptionMu.RLock()
+	key := decryptionKey
+	decryptionMu.RUnlock()
+
+	var decrypt func(value interface{}, key string) (interface{}, error)
+	decrypt = func(value interface{}, k string) (interface{}, error) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for kk, vv := range v {
+				decrypted, err := decrypt(vv, joinKey(k, kk))
+				if err != nil {
+					return nil, err
+				}
+				v[kk] = decrypted
+			}
+		case []interface{}:
+			for i, vv := range v {
+				decrypted, err := decrypt(vv, joinKey(k, strconv.Itoa(i)))
+				if err != nil {
+					return nil, err
+				}
+				v[i] = decrypted
+			}
+		case string:
+			if !strings.HasPrefix(v, encryptedPrefix) || !strings.HasSuffix(v, encryptedSuffix) {
+				return v, nil
+			}
+			if key == nil {
+				return nil, fmt.Errorf("Need a decryption key for the encrypted value. (key: %s)", k)
+			}
+			plaintext, err := decryptValue(v, key)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to decrypt the value. (key: %s, error: %s)", k, err)
+			}
+			secrets[k] = true
+			return plaintext, nil
+		}
+		return value, nil
+	}
+	_, err := decrypt(config, "")
+	return err
+}
+
+
+//
+// Decrypt the "ENC[AES256_GCM,...]" value.
+//
+func decryptValue(value string, key []byte) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(value, encryptedPrefix), encryptedSuffix))
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("Encrypted value is too short.")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+
+//
+// New AES-GCM cipher with the key.
+//
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, fmt.Errorf("Need a %d byte encryption key. (size: %d)", EncryptionKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
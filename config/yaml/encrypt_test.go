@@ -0,0 +1,68 @@
+//
+// encrypt_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test decrypting encrypted values on load.
+//
+func TestEncrypt(t *testing.T) {
+	key := make([]byte, yaml.EncryptionKeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	encrypted, err := yaml.Encrypt("s3cret", key)
+	if err != nil {
+		t.Fatalf("Failed to encrypt. Error: %v\n", err)
+	}
+	if !strings.HasPrefix(encrypted, "ENC[AES256_GCM,") || strings.Contains(encrypted, "s3cret") {
+		t.Fatalf("Unexpected encrypted value: %s\n", encrypted)
+	}
+
+	file, err := createTempYAMLFile("database:\n  password: \"" + encrypted + "\"\n  dsn: app:${ref:database.password}@db\n")
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	// No key.
+	if err := yaml.NewStore(nil).InitSources(context.Background(), yaml.NewFileSource(file)); err == nil || !strings.Contains(err.Error(), "database.password") {
+		t.Errorf("Expected an error for the missing key, actual: %v\n", err)
+	}
+
+	if err := yaml.SetDecryptionKey(key); err != nil {
+		t.Fatalf("Failed to set key. Error: %v\n", err)
+	}
+	defer yaml.SetDecryptionKey(nil)
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	if v := store.GetString("database.password"); v != "s3cret" {
+		t.Errorf("Expected the decrypted value, actual: %s\n", v)
+	}
+	if v := store.GetString("database.dsn"); v != "app:s3cret@db" {
+		t.Errorf("Expected the decrypted reference, actual: %s\n", v)
+	}
+
+	// Wrong key.
+	wrongKey := make([]byte, yaml.EncryptionKeySize)
+	yaml.SetDecryptionKey(wrongKey)
+	if err := yaml.NewStore(nil).InitSources(context.Background(), yaml.NewFileSource(file)); err == nil {
+		t.Errorf("Expected an error for the wrong key.\n")
+	}
+	if err := yaml.SetDecryptionKey([]byte("short")); err == nil {
+		t.Errorf("Expected an error for a short key.\n")
+	}
+}
@@ -0,0 +1,263 @@
+//
+// flags.go
+//
+// Package flags evaluates feature flags defined in the "features" section of the config.
+//
+//   features:
+//     new-checkout:
+//       enabled: true
+//       percentage: 25           # rollout by subject hash
+//       allow: [user-1, user-2]  # always on
+//       deny: [user-3]           # always off
+//       start: 2026-01-01T00:00:00Z
+//       end: 2026-02-01T00:00:00Z
+//     dark-mode: on
+//
+package flags
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"time"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+var (
+	// Key of the config section holding the flags.
+	Section = "features"
+
+	std = New(yaml.Default())
+
+	errNotDefined = fmt.Errorf("Feature flag is not defined.")
+)
+
+
+//
+// Flag is the definition of a feature flag.
+// Percentage defaults to 100, or 0 if the allow list is set.
+//
+type Flag struct {
+	Name       string
+	Enabled    bool
+	Percentage float64
+	Allow      []string
+	Deny       []string
+	Start      time.Time
+	End        time.Time
+}
+
+
+//
+// Evaluator evaluates the flags of a store.
+// Flags are read from the store on each evaluation, so they follow reloads.
+//
+type Evaluator struct {
+	store *yaml.Store
+	now   func() time.Time
+}
+
+
+//
+// New Evaluator
+//
+func New(store *yaml.Store) *Evaluator {
+	return &Evaluator{
+		store: store,
+		now: time.Now,
+	}
+}
+
+
+//
+// Check if the flag is enabled for the subject (e.g. a user ID) in the default config.
+//
+func Enabled(name, subject string) bool {
+	return std.Enabled(name, subject)
+}
+
+
+//
+// Get the flag definition in the default config.
+//
+func Lookup(name string) (*Flag, error) {
+	return std.Lookup(name)
+}
+
+
+//
+// Check if the flag is enabled for the subject.
+// Undefined or invalid flags are disabled.
+//
+func (e *Evaluator) Enabled(name, subject string) bool {
+	flag, err := e.Lookup(name)
+	if err != nil {
+		if err != errNotDefined {
+			log.Printf("[WARN] %s\n", err)
+		}
+		return false
+	}
+	return flag.Evaluate(subject, e.now())
+}
+
+
+//
+// Get the flag definition.
+//
+func (e *Evaluator) Lookup(name string) (*Flag, error) {
+	v, err := yaml.GetFrom[interface{}](e.store, Section + "." + name)
+	if err != nil {
+		return nil, errNotDefined
+	}
+	return parseFlag(name, v)
+}
+
+
+//
+// Check if the flag is enabled for the subject at the time.
+//
+func (f *Flag) Evaluate(subject string, now time.Time) bool {
+	if !f.Enabled {
+		return false
+	}
+	if !f.Start.IsZero() && now.Before(f.Start) {
+		return false
+	}
+	if !f.End.IsZero() && !now.Before(f.End) {
+		return false
+	}
+	for _, s := range f.Deny {
+		if s == subject {
+			return false
+		}
+	}
+	for _, s := range f.Allow {
+		if s == subject {
+			return true
+		}
+	}
+	if f.Percentage >= 100 {
+		return true
+	}
+	if f.Percentage <= 0 {
+		return false
+	}
+	return float64(bucket(f.Name, subject)) < f.Percentage * 100
+}
+
+
+//
+// Get the deterministic bucket (0-9999) of the subject for the flag.
+//
+func bucket(name, subject string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(subject))
+	return h.Sum32() % 10000
+}
+
+
+//
+// Parse the flag definition from the config value.
+//
+func parseFlag(name string, v interface{}) (*Flag, error) {
+	switch value := v.(type) {
+	case bool:
+		return &Flag{Name: name, Enabled: value, Percentage: 100}, nil
+	case string:
+		switch value {
+		case "on", "true":
+			return &Flag{Name: name, Enabled: true, Percentage: 100}, nil
+		case "off", "false":
+			return &Flag{Name: name, Percentage: 100}, nil
+		}
+	case map[string]interface{}:
+		return parseFlagMap(name, value)
+	}
+	return nil, fmt.Errorf("Invalid feature flag. (name: %s, value: %v)", name, v)
+}
+
+
+//
+// Parse the flag definition from the config map.
+//
+func parseFlagMap(name string, m map[string]interface{}) (*Flag, error) {
+	flag := &Flag{Name: name, Enabled: true, Percentage: 100}
+	var err error
+
+	if v, ok := m["enabled"]; ok {
+		enabled, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("Invalid enabled of feature flag. (name: %s, value: %v)", name, v)
+		}
+		flag.Enabled = enabled
+	}
+	if flag.Allow, err = stringList(name, "allow", m["allow"]); err != nil {
+		return nil, err
+	}
+	if flag.Deny, err = stringList(name, "deny", m["deny"]); err != nil {
+		return nil, err
+	}
+	if len(flag.Allow) > 0 {
+		flag.Percentage = 0
+	}
+	if v, ok := m["percentage"]; ok {
+		switch p := v.(type) {
+		case int:
+			flag.Percentage = float64(p)
+		case float64:
+			flag.Percentage = p
+		default:
+			return nil, fmt.Errorf("Invalid percentage of feature flag. (name: %s, value: %v)", name, v)
+		}
+	}
+	if flag.Start, err = parseTime(name, "start", m["start"]); err != nil {
+		return nil, err
+	}
+	if flag.End, err = parseTime(name, "end", m["end"]); err != nil {
+		return nil, err
+	}
+	return flag, nil
+}
+
+
+//
+// Parse the list of subjects.
+//
+func stringList(name, key string, v interface{}) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Invalid %s of feature flag. (name: %s, value: %v)", key, name, v)
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, fmt.Sprint(item))
+	}
+	return result, nil
+}
+
+
+//
+// Parse the RFC 3339 time or date.
+//
+func parseTime(name, key string, v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return t, nil
+	case string:
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("Invalid %s of feature flag. (name: %s, value: %v)", key, name, v)
+}
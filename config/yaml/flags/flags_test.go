@@ -0,0 +1,109 @@
+//
+// flags_test.go
+//
+package flags_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/k4k3ru-hub/go/config/yaml/flags"
+	"github.com/k4k3ru-hub/go/config/yaml/yamltest"
+)
+
+
+//
+// Test on/off, allow/deny lists and missing flags.
+//
+func TestEnabled(t *testing.T) {
+	store := yamltest.NewString(t, `
+features:
+  dark-mode: on
+  legacy: false
+  beta:
+    allow: [user-1]
+    deny: [user-2]
+  disabled:
+    enabled: false
+    allow: [user-1]
+`)
+	evaluator := flags.New(store)
+
+	tests := []struct {
+		name    string
+		subject string
+		want    bool
+	}{
+		{"dark-mode", "anyone", true},
+		{"legacy", "anyone", false},
+		{"beta", "user-1", true},
+		{"beta", "user-2", false},
+		{"beta", "user-3", false},
+		{"disabled", "user-1", false},
+		{"missing", "user-1", false},
+	}
+	for _, tt := range tests {
+		if got := evaluator.Enabled(tt.name, tt.subject); got != tt.want {
+			t.Errorf("Unexpected result. (flag: %s, subject: %s, expected: %t, actual: %t)\n", tt.name, tt.subject, tt.want, got)
+		}
+	}
+}
+
+
+//
+// Test percentage rollouts being deterministic and roughly proportional.
+//
+func TestEnabled_Percentage(t *testing.T) {
+	store := yamltest.NewString(t, `
+features:
+  new-checkout:
+    percentage: 25
+`)
+	evaluator := flags.New(store)
+
+	enabled := 0
+	for i := 0; i < 10000; i++ {
+		subject := fmt.Sprintf("user-%d", i)
+		result := evaluator.Enabled("new-checkout", subject)
+		if result != evaluator.Enabled("new-checkout", subject) {
+			t.Fatalf("Result is not deterministic. (subject: %s)\n", subject)
+		}
+		if result {
+			enabled++
+		}
+	}
+	if enabled < 2300 || enabled > 2700 {
+		t.Errorf("Unexpected rollout. Expected: about 2500, actual: %d\n", enabled)
+	}
+}
+
+
+//
+// Test time windows.
+//
+func TestFlag_Evaluate_TimeWindow(t *testing.T) {
+	store := yamltest.NewString(t, `
+features:
+  campaign:
+    start: 2026-01-01T00:00:00Z
+    end: 2026-02-01T00:00:00Z
+`)
+	flag, err := flags.New(store).Lookup("campaign")
+	if err != nil {
+		t.Fatalf("Failed to look up. Error: %v\n", err)
+	}
+	for _, tt := range []struct {
+		now  string
+		want bool
+	}{
+		{"2025-12-31T23:59:59Z", false},
+		{"2026-01-15T00:00:00Z", true},
+		{"2026-02-01T00:00:00Z", false},
+	} {
+		now, _ := time.Parse(time.RFC3339, tt.now)
+		if got := flag.Evaluate("user-1", now); got != tt.want {
+			t.Errorf("Unexpected result. (now: %s, expected: %t, actual: %t)\n", tt.now, tt.want, got)
+		}
+	}
+}
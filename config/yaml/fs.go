@@ -0,0 +1,100 @@
+//
+// fs.go
+//
+package yaml
+
+import (
+	"context"
+	"io/fs"
+)
+
+
+var (
+	defaultSources []Source
+)
+
+
+type FSSource struct {
+	FS   fs.FS
+	Path string
+}
+
+
+//
+// New FSSource
+//
+func NewFSSource(fsys fs.FS, path string) *FSSource {
+	return &FSSource{
+		FS: fsys,
+		Path: path,
+	}
+}
+
+
+//
+// Set the files in the file system as the base layer which Init merges before the -yaml files.
+// It is typically used with embedded defaults.
+//
+//   //go:embed defaults.yaml
+//   var defaults embed.FS
+//
+//   yaml.SetDefaults(defaults, "defaults.yaml")
+//
+func SetDefaults(fsys fs.FS, paths ...string) {
+	extraSourcesMu.Lock()
+	defer extraSourcesMu.Unlock()
+	defaultSources = nil
+	for _, path := range paths {
+		defaultSources = append(defaultSources, NewFSSource(fsys, path))
+	}
+}
+
+
+//
+// Initialize from the files in the file system.
+//
+func (s *Store) InitFromFS(fsys fs.FS, paths ...string) error {
+	var sources []Source
+	for _, path := range paths {
+		sources = append(sources, NewFSSource(fsys, path))
+	}
+	return s.InitSources(context.Background(), sources...)
+}
+
+
+//
+// Load the YAML file from the file system.
+//
+func (s *FSSource) Load(ctx context.Context) (map[string]interface{}, error) {
+	result, err := s.loadDetailed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.config, nil
+}
+
+
+//
+// Load and verify the YAML file from the file system with the line of each key.
+//
+func (s *FSSource) loadDetailed(ctx context.Context) (*loadResult, error) {
+	bytes, err := fs.ReadFile(s.FS, s.Path)
+	if err != nil {
+		return nil, err
+	}
+	readFile := func(name string) ([]byte, error) {
+		return fs.ReadFile(s.FS, name)
+	}
+	digest, err := verifyFile(readFile, s.Path, bytes)
+	if err != nil {
+		return nil, err
+	}
+	result, err := unmarshalConfig(bytes, s.Path)
+	if err != nil {
+		return nil, err
+	}
+	if digest != "" {
+		result.digests[s.Path] = digest
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+//
+// fs_test.go
+//
+package yaml_test
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test Init merging -yaml files on top of the defaults.
+//
+func TestInit_Defaults(t *testing.T) {
+	yaml.SetDefaults(fstest.MapFS{
+		"defaults.yaml": {Data: []byte("server:\n  host: localhost\n  port: 8080\n")},
+	}, "defaults.yaml")
+	defer yaml.SetDefaults(nil)
+
+	file, err := createTempYAMLFile(`
+server:
+  port: 9090
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	os.Args = []string{"cmd", "-yaml", file}
+	if err := yaml.Init(); err != nil {
+		t.Fatalf("Failed to execute Init. Error: %v\n", err)
+	}
+	if val := yaml.GetString("server.host"); val != "localhost" {
+		t.Errorf("Failed to read server.host value. Expected: localhost, actual: %s\n", val)
+	}
+	if val := yaml.GetInt("server.port"); val != 9090 {
+		t.Errorf("Failed to read server.port value. Expected: 9090, actual: %d\n", val)
+	}
+
+	// The defaults are enough without -yaml.
+	os.Args = []string{"cmd"}
+	if err := yaml.Init(); err != nil {
+		t.Errorf("Failed to execute Init without -yaml. Error: %v\n", err)
+	}
+}
+
+
+//
+// Test InitFromFS returning an error for a missing file.
+//
+func TestInitFromFS_Missing(t *testing.T) {
+	store := yaml.NewStore(nil)
+	if err := store.InitFromFS(fstest.MapFS{}, "missing.yaml"); err == nil {
+		t.Errorf("Expected an error for a missing file.\n")
+	}
+}
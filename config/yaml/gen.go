@@ -0,0 +1,256 @@
+//
+// gen.go
+//
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+
+//
+// FieldDoc describes a config key.
+// Sections (nested structs) have the type "object".
+//
+type FieldDoc struct {
+	Key     string
+	Type    string
+	Default string
+	Desc    string
+}
+
+
+//
+// Describe every key of the config struct by its yaml, default and desc tags.
+// Types decoded by a converter, yaml.Unmarshaler or encoding.TextUnmarshaler are described as single keys,
+// and a struct nested in itself is described once without its fields.
+//
+//   type Config struct {
+//       Server struct {
+//           Port int `yaml:"port" default:"8080" desc:"Listen port"`
+//       } `yaml:"server" desc:"HTTP server"`
+//   }
+//
+func DescribeStruct(v interface{}) ([]FieldDoc, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Need a struct to describe. (type: %v)", reflect.TypeOf(v))
+	}
+	var result []FieldDoc
+	describeFields(t, "", map[reflect.Type]bool{t: true}, &result)
+	return result, nil
+}
+
+
+//
+// Write a commented sample YAML of the keys.
+//
+func WriteSample(w io.Writer, fields []FieldDoc) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range fields {
+		keys := strings.Split(field.Key, ".")
+		node := root
+		for _, k := range keys[:len(keys)-1] {
+			node = sampleChild(node, k)
+		}
+
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: keys[len(keys)-1], HeadComment: sampleComment(field)}
+		var valueNode *yaml.Node
+		if field.Type == "object" {
+			valueNode = &yaml.Node{Kind: yaml.MappingNode}
+		} else {
+			valueNode = sampleValue(field)
+		}
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+
+//
+// Write a Markdown reference table of the keys.
+//
+func WriteReference(w io.Writer, fields []FieldDoc) error {
+	var b strings.Builder
+	b.WriteString("| Key | Type | Default | Description |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, field := range fields {
+		def := ""
+		if field.Default != "" {
+			def = "`" + field.Default + "`"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n",
+			field.Key,
+			escapeMarkdown(field.Type),
+			escapeMarkdown(def),
+			escapeMarkdown(field.Desc))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+
+//
+// Describe the fields of the struct type recursively.
+// The parents are the struct types being described, which are not described again.
+//
+func describeFields(t reflect.Type, prefix string, parents map[reflect.Type]bool, result *[]FieldDoc) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, inline := fieldKey(field)
+		if name == "-" {
+			continue
+		}
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if inline {
+			if ft.Kind() == reflect.Struct && !parents[ft] {
+				parents[ft] = true
+				describeFields(ft, prefix, parents, result)
+				delete(parents, ft)
+			}
+			continue
+		}
+
+		key := joinKey(prefix, name)
+		typeName := docTypeName(field.Type)
+		*result = append(*result, FieldDoc{
+			Key: key,
+			Type: typeName,
+			Default: field.Tag.Get("default"),
+			Desc: field.Tag.Get("desc"),
+		})
+		if typeName == "object" && !parents[ft] {
+			parents[ft] = true
+			describeFields(ft, key, parents, result)
+			delete(parents, ft)
+		}
+	}
+}
+
+
+//
+// Get the type name used in docs.
+//
+func docTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == reflect.TypeOf(time.Duration(0)):
+		return "duration"
+	case t == reflect.TypeOf(time.Time{}):
+		return "time"
+	case hasCustomConverter(t):
+		return t.String()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "[]" + docTypeName(t.Elem())
+	case reflect.Map:
+		return "map[" + docTypeName(t.Key()) + "]" + docTypeName(t.Elem())
+	case reflect.Interface:
+		return "any"
+	default:
+		return t.Kind().String()
+	}
+}
+
+
+//
+// Get or create the child mapping of the sample node.
+//
+func sampleChild(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+	return child
+}
+
+
+//
+// Get the comment of the sample key.
+//
+func sampleComment(field FieldDoc) string {
+	comment := field.Desc
+	if field.Type != "object" {
+		if comment != "" {
+			comment += " "
+		}
+		comment += "(" + field.Type + ")"
+	}
+	return comment
+}
+
+
+//
+// Get the sample value from the default or the zero value of the type.
+//
+func sampleValue(field FieldDoc) *yaml.Node {
+	if field.Default != "" {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(field.Default), &node); err == nil && len(node.Content) > 0 {
+			return node.Content[0]
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: field.Default}
+	}
+
+	switch {
+	case strings.HasPrefix(field.Type, "[]"):
+		return &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	case strings.HasPrefix(field.Type, "map["):
+		return &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	}
+	switch field.Type {
+	case "bool":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "0"}
+	case "float32", "float64":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: "0.0"}
+	case "string", "duration":
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: ""}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+
+//
+// Escape the Markdown table cell.
+//
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,102 @@
+//
+// gen_test.go
+//
+package yaml_test
+
+import (
+	"bytes"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+type testDocConfig struct {
+	Server struct {
+		Port    int           `yaml:"port" default:"8080" desc:"Listen port"`
+		Timeout time.Duration `yaml:"timeout" default:"30s" desc:"Request timeout"`
+	} `yaml:"server" desc:"HTTP server"`
+	Hosts []string `yaml:"hosts" desc:"Upstream hosts"`
+}
+
+
+type testDocNode struct {
+	Name     string         `yaml:"name"`
+	Endpoint url.URL        `yaml:"endpoint"`
+	Allow    []net.IPNet    `yaml:"allow"`
+	Parent   *testDocNode   `yaml:"parent"`
+	Children []*testDocNode `yaml:"children"`
+}
+
+
+//
+// Test generating the sample YAML and the reference.
+//
+func TestDescribeStruct(t *testing.T) {
+	fields, err := yaml.DescribeStruct(testDocConfig{})
+	if err != nil {
+		t.Fatalf("Failed to describe. Error: %v\n", err)
+	}
+	want := []yaml.FieldDoc{
+		{Key: "server", Type: "object", Desc: "HTTP server"},
+		{Key: "server.port", Type: "int", Default: "8080", Desc: "Listen port"},
+		{Key: "server.timeout", Type: "duration", Default: "30s", Desc: "Request timeout"},
+		{Key: "hosts", Type: "[]string", Desc: "Upstream hosts"},
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("Unexpected fields: %+v\n", fields)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("Unexpected field. Expected: %+v, actual: %+v\n", want[i], fields[i])
+		}
+	}
+
+	var sample bytes.Buffer
+	if err := yaml.WriteSample(&sample, fields); err != nil {
+		t.Fatalf("Failed to write sample. Error: %v\n", err)
+	}
+	for _, s := range []string{"# HTTP server\nserver:", "  # Listen port (int)\n  port: 8080", "hosts: []"} {
+		if !strings.Contains(sample.String(), s) {
+			t.Errorf("Sample does not contain %q.\n%s", s, sample.String())
+		}
+	}
+
+	var reference bytes.Buffer
+	if err := yaml.WriteReference(&reference, fields); err != nil {
+		t.Fatalf("Failed to write reference. Error: %v\n", err)
+	}
+	if !strings.Contains(reference.String(), "| `server.port` | int | `8080` | Listen port |") {
+		t.Errorf("Reference does not contain server.port.\n%s", reference.String())
+	}
+}
+
+
+//
+// Test describing self-referential structs and types decoded by converters.
+//
+func TestDescribeStruct_Recursive(t *testing.T) {
+	fields, err := yaml.DescribeStruct(&testDocNode{})
+	if err != nil {
+		t.Fatalf("Failed to describe. Error: %v\n", err)
+	}
+	want := []yaml.FieldDoc{
+		{Key: "name", Type: "string"},
+		{Key: "endpoint", Type: "url.URL"},
+		{Key: "allow", Type: "[]net.IPNet"},
+		{Key: "parent", Type: "object"},
+		{Key: "children", Type: "[]object"},
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("Unexpected fields: %+v\n", fields)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("Unexpected field. Expected: %+v, actual: %+v\n", want[i], fields[i])
+		}
+	}
+}
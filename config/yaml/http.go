@@ -0,0 +1,302 @@
+//
+// http.go
+//
+package yaml
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+
+var (
+	// HTTP client used to fetch config from http(s) URLs.
+	HTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+	// Directory where the last good body of each URL is cached for offline startup.
+	// It defaults to a directory under os.UserCacheDir, and an empty string disables the cache.
+	HTTPCacheDir = defaultHTTPCacheDir()
+
+	// Default interval to re-fetch URLs while watching.
+	HTTPPollInterval = time.Minute
+)
+
+
+//
+// HTTPSource fetches the config from a URL.
+// The last ETag and body are kept in memory, so polling sends If-None-Match even without the disk cache.
+//
+type HTTPSource struct {
+	URL      string
+	Interval time.Duration
+
+	mu      sync.Mutex
+	etag    string
+	body    []byte
+	fetched bool
+}
+
+
+//
+// New HTTPSource
+//
+func NewHTTPSource(url string) *HTTPSource {
+	return &HTTPSource{
+		URL: url,
+		Interval: HTTPPollInterval,
+	}
+}
+
+
+//
+// Fetch and load the YAML.
+//
+func (s *HTTPSource) Load(ctx context.Context) (map[string]interface{}, error) {
+	result, err := s.loadDetailed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.config, nil
+}
+
+
+//
+// Fetch, verify and load the YAML with the line of each key.
+// Both fetched and cached bodies are verified against the sidecar files of the URL.
+//
+func (s *HTTPSource) loadDetailed(ctx context.Context) (*loadResult, error) {
+	bytes, _, err := s.fetch(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := verifyFile(func(url string) ([]byte, error) {
+		return fetchSidecar(ctx, url)
+	}, s.URL, bytes)
+	if err != nil {
+		return nil, err
+	}
+	result, err := unmarshalConfig(bytes, s.URL)
+	if err != nil {
+		return nil, err
+	}
+	if digest != "" {
+		result.digests[s.URL] = digest
+	}
+	return result, nil
+}
+
+
+//
+// Re-fetch every interval, and notify when the server returned a new body.
+//
+func (s *HTTPSource) Watch(ctx context.Context, notify func()) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = HTTPPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			_, changed, err := s.fetch(ctx, false)
+			if err != nil {
+				log.Printf("[WARN] %s\n", err)
+				continue
+			}
+			if changed {
+				notify()
+			}
+		}
+	}
+}
+
+
+//
+// Fetch the config body from the URL.
+// The last ETag is sent as If-None-Match, and the last body is used
+// when the server returns 304 or cannot be reached.
+// The changed result is true only if the server returned a new body.
+// A load right after Watch fetched a new body uses it without another request.
+//
+func (s *HTTPSource) fetch(ctx context.Context, load bool) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if load && s.fetched {
+		s.fetched = false
+		return s.body, false, nil
+	}
+	if s.body == nil {
+		s.body, s.etag = readHTTPCache(s.URL)
+	}
+
+	body, etag, err := requestHTTP(ctx, s.URL, s.etag)
+	if err != nil {
+		if s.body != nil {
+			log.Printf("[WARN] %s Using the cached config. (url: %s)\n", err, s.URL)
+			return s.body, false, nil
+		}
+		return nil, false, err
+	}
+
+	// Not modified.
+	if body == nil {
+		if s.body == nil {
+			return nil, false, fmt.Errorf("Received 304 but no cached config exists. (url: %s)", s.URL)
+		}
+		return s.body, false, nil
+	}
+
+	s.body, s.etag = body, etag
+	s.fetched = !load
+	if err := writeHTTPCache(s.URL, body, etag); err != nil {
+		log.Printf("[WARN] Failed to cache config. (url: %s, error: %s)\n", s.URL, err)
+	}
+	return body, true, nil
+}
+
+
+//
+// Fetch a sidecar file of the URL, such as its checksum or signature.
+// The last fetched sidecar is used when the server cannot be reached, so cached bodies can be verified offline.
+//
+func fetchSidecar(ctx context.Context, url string) ([]byte, error) {
+	body, _, err := requestHTTP(ctx, url, "")
+	if err != nil {
+		if cached, _ := readHTTPCache(url); cached != nil {
+			return cached, nil
+		}
+		return nil, err
+	}
+	if err := writeHTTPCache(url, body, ""); err != nil {
+		log.Printf("[WARN] Failed to cache config. (url: %s, error: %s)\n", url, err)
+	}
+	return body, nil
+}
+
+
+//
+// Send a GET request.
+// The body is nil if the server returned 304 Not Modified.
+//
+func requestHTTP(ctx context.Context, url, etag string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	res, err := HTTPClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to fetch config. (url: %s, error: %s)", url, err)
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("Failed to read config. (url: %s, error: %s)", url, err)
+		}
+		return body, res.Header.Get("ETag"), nil
+	case http.StatusNotModified:
+		return nil, etag, nil
+	default:
+		return nil, "", fmt.Errorf("Failed to fetch config. (url: %s, status: %d)", url, res.StatusCode)
+	}
+}
+
+
+//
+// Get the default cache directory under the user cache directory.
+//
+func defaultHTTPCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "k4k3ru-go-config-yaml")
+}
+
+
+//
+// Get the cache file path of the URL.
+//
+func httpCachePath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(HTTPCacheDir, hex.EncodeToString(sum[:]))
+}
+
+
+//
+// Read the cached body and ETag of the URL.
+//
+func readHTTPCache(url string) ([]byte, string) {
+	if HTTPCacheDir == "" {
+		return nil, ""
+	}
+	path := httpCachePath(url)
+	body, err := os.ReadFile(path + ".yaml")
+	if err != nil {
+		return nil, ""
+	}
+	etag, _ := os.ReadFile(path + ".etag")
+	return body, string(etag)
+}
+
+
+//
+// Write the body and ETag of the URL to the cache.
+//
+func writeHTTPCache(url string, body []byte, etag string) error {
+	if HTTPCacheDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(HTTPCacheDir, 0700); err != nil {
+		return err
+	}
+	path := httpCachePath(url)
+	if err := writeFileAtomic(path + ".yaml", body, 0600); err != nil {
+		return err
+	}
+	return writeFileAtomic(path + ".etag", []byte(etag), 0600)
+}
+
+
+//
+// Write a file via a temporary file and rename.
+//
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "." + filepath.Base(path) + ".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,211 @@
+//
+// http_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test Init and Reload with an http URL.
+//
+func TestInit_HTTP(t *testing.T) {
+	setHTTPCacheDir(t, t.TempDir())
+
+	var notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("key1: remote_value\n"))
+	}))
+
+	os.Args = []string{"cmd", "-yaml", server.URL}
+	if err := yaml.Init(); err != nil {
+		t.Fatalf("Failed to execute Init. Error: %v\n", err)
+	}
+	if val := yaml.GetString("key1"); val != "remote_value" {
+		t.Errorf("Failed to read key1 value. Expected: remote_value, actual: %s\n", val)
+	}
+
+	// The cached ETag must be sent on reload.
+	if err := yaml.Reload(); err != nil {
+		t.Fatalf("Failed to execute Reload. Error: %v\n", err)
+	}
+	if n := atomic.LoadInt32(&notModified); n != 1 {
+		t.Errorf("Expected one 304 response, actual: %d\n", n)
+	}
+	if val := yaml.GetString("key1"); val != "remote_value" {
+		t.Errorf("Failed to read key1 value after 304. Expected: remote_value, actual: %s\n", val)
+	}
+
+	// The cached body must be used while the server is down.
+	server.Close()
+	if err := yaml.Reload(); err != nil {
+		t.Fatalf("Failed to execute Reload while offline. Error: %v\n", err)
+	}
+	if val := yaml.GetString("key1"); val != "remote_value" {
+		t.Errorf("Failed to read key1 value while offline. Expected: remote_value, actual: %s\n", val)
+	}
+}
+
+
+//
+// Test Init with an unreachable URL and no cache.
+//
+func TestInit_HTTPWithoutCache(t *testing.T) {
+	setHTTPCacheDir(t, t.TempDir())
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	os.Args = []string{"cmd", "-yaml", server.URL}
+	if err := yaml.Init(); err == nil {
+		t.Errorf("Expected an error for 404 without cache.\n")
+	}
+}
+
+
+//
+// Test Watch notifying only when the body changed, without the disk cache.
+//
+func TestHTTPSource_Watch(t *testing.T) {
+	setHTTPCacheDir(t, "")
+
+	var version, requests, notModified int32
+	version = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		etag := fmt.Sprintf(`"v%d"`, atomic.LoadInt32(&version))
+		if r.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprintf(w, "version: %d\n", atomic.LoadInt32(&version))
+	}))
+	defer server.Close()
+
+	src := yaml.NewHTTPSource(server.URL)
+	src.Interval = 10 * time.Millisecond
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), src); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	reloaded := make(chan struct{}, 10)
+	store.OnReload(func() {
+		reloaded <- struct{}{}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := store.StartWatching(ctx); err != nil {
+		t.Fatalf("Failed to watch. Error: %v\n", err)
+	}
+
+	// Unchanged polls are answered with 304 and do not reload.
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-reloaded:
+		t.Fatalf("Unexpected reload without a change.\n")
+	default:
+	}
+	if atomic.LoadInt32(&notModified) == 0 {
+		t.Fatalf("Expected If-None-Match on polls.\n")
+	}
+
+	atomic.StoreInt32(&version, 2)
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected a reload after the change.\n")
+	}
+	cancel()
+	if v := store.GetInt("version"); v != 2 {
+		t.Errorf("Expected version 2, actual: %d\n", v)
+	}
+	// The reload reuses the body fetched by Watch.
+	if n := atomic.LoadInt32(&requests) - atomic.LoadInt32(&notModified); n != 2 {
+		t.Errorf("Expected 2 full responses, actual: %d\n", n)
+	}
+}
+
+
+//
+// Test verifying fetched and cached bodies by the sidecar checksum of the URL.
+//
+func TestHTTPSource_Verify(t *testing.T) {
+	setHTTPCacheDir(t, t.TempDir())
+
+	content := "key1: value1\n"
+	sum := sha256.Sum256([]byte(content))
+	var body atomic.Value
+	body.Store(content)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.yaml":
+			w.Write([]byte(body.Load().(string)))
+		case "/config.yaml.sha256":
+			w.Write([]byte(hex.EncodeToString(sum[:]) + "  config.yaml\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	url := server.URL + "/config.yaml"
+
+	option := yaml.NewVerifyOption()
+	option.Checksum = true
+	yaml.SetVerifyOption(option)
+	defer yaml.SetVerifyOption(nil)
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewHTTPSource(url)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	if digest := store.Metadata().Digests[url]; digest != "sha256:" + hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected digest: %s\n", digest)
+	}
+
+	// A tampered body must be refused on reload.
+	body.Store("key1: tampered\n")
+	if err := store.Reload(); err == nil {
+		t.Errorf("Expected an error for a tampered body.\n")
+	}
+	if val := store.GetString("key1"); val != "value1" {
+		t.Errorf("Failed to keep key1 value. Expected: value1, actual: %s\n", val)
+	}
+
+	// The tampered body is cached, and must be refused offline too.
+	server.Close()
+	if err := yaml.NewStore(nil).InitSources(context.Background(), yaml.NewHTTPSource(url)); err == nil {
+		t.Errorf("Expected an error for a tampered cached body.\n")
+	}
+}
+
+
+//
+// Set the HTTP cache directory, and restore it when the test finishes.
+//
+func setHTTPCacheDir(t *testing.T, dir string) {
+	old := yaml.HTTPCacheDir
+	yaml.HTTPCacheDir = dir
+	t.Cleanup(func() {
+		yaml.HTTPCacheDir = old
+	})
+}
@@ -0,0 +1,268 @@
+//
+// key.go
+//
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+
+var (
+	// Generation of the global settings affecting lookups and conversions.
+	// It is bumped by SetLenient, SetNormalizeOption and the Register functions to drop the cached values.
+	settingsGen atomic.Uint64
+)
+
+
+//
+// KeyPath is a dotted key split in advance.
+// Create it once with Key and reuse it in hot paths.
+//
+//   var timeoutKey = yaml.Key("http.timeout")
+//   timeout, err := yaml.GetKey[time.Duration](timeoutKey)
+//
+type KeyPath struct {
+	key  string
+	path []string
+}
+
+
+//
+// lookupCache caches the values converted from the current config.
+// Entries are valid for the config version, the config map and the settings they were read with,
+// and the cache is reset whenever Set, Replace or Rollback changes the config.
+//
+type lookupCache struct {
+	mu       sync.RWMutex
+	gen      uint64
+	version  uint64
+	settings uint64
+	root     uintptr
+	values   map[cacheKey]interface{}
+}
+type cacheKey struct {
+	key string
+	t   reflect.Type
+}
+
+
+//
+// Compile the dotted key.
+//
+func Key(key string) *KeyPath {
+	return &KeyPath{
+		key: key,
+		path: strings.Split(key, "."),
+	}
+}
+
+
+//
+// Get the value of the precompiled key converted to T.
+//
+func GetKey[T any](k *KeyPath) (T, error) {
+	return GetKeyFrom[T](std, k)
+}
+
+
+//
+// Get the value of the precompiled key in the store converted to T.
+//
+func GetKeyFrom[T any](s *Store, k *KeyPath) (T, error) {
+	return getAs[T](s, k.key, k.path)
+}
+
+
+//
+// Get the dotted key.
+//
+func (k *KeyPath) String() string {
+	return k.key
+}
+
+
+//
+// Get the value converted to T through the cache.
+// The path is split from the key if it is nil.
+//
+func getAs[T any](s *Store, key string, path []string) (T, error) {
+	var result T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	v, err := s.get(key, path, t)
+	if err != nil {
+		return result, err
+	}
+	return v.(T), nil
+}
+
+
+//
+// Get the value of the key, and convert it through the cache.
+// A nil type returns the raw config value, otherwise the value converted to the type.
+// Cached values are returned without walking the config.
+//
+func (s *Store) get(key string, path []string, t reflect.Type) (interface{}, error) {
+	ck := cacheKey{key: key, t: t}
+
+	s.mu.RLock()
+	version := s.version
+	root := reflect.ValueOf(*s.config).Pointer()
+	settings := settingsGen.Load()
+	cached, gen, ok := s.cache.load(ck, version, root, settings)
+	if ok {
+		s.mu.RUnlock()
+		return cached, nil
+	}
+	option := getNormalizeOption()
+	var v interface{}
+	switch {
+	case path != nil:
+		v = lookupPath(*s.config, option.normalizePath(path))
+	case option == nil:
+		v = lookupKey(*s.config, key)
+	default:
+		v = lookupPath(*s.config, option.normalizePath(strings.Split(key, ".")))
+	}
+	if v == nil {
+		// Fall back to the old key of the alias.
+		if oldKey, ok := lookupAlias(key); ok {
+			v = lookupPath(*s.config, option.normalizePath(strings.Split(oldKey, ".")))
+		}
+	}
+	s.mu.RUnlock()
+
+	if v == nil {
+		if t == nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w (key: %s)", ErrKeyNotFound, key)
+	}
+	if t == nil {
+		return v, nil
+	}
+	converted, err := convertTo(v, t)
+	if err != nil {
+		return nil, fmt.Errorf("%s (key: %s)", err, key)
+	}
+	if isCacheable(t) && reflect.TypeOf(v).Comparable() {
+		s.cache.store(ck, converted, version, root, gen, settings)
+	}
+	return converted, nil
+}
+
+
+//
+// Look up the value of the path in the config.
+//
+func lookupPath(config map[string]interface{}, path []string) interface{} {
+	var v interface{} = config
+	for _, k := range path {
+		if v = lookupChild(v, k); v == nil {
+			return nil
+		}
+	}
+	return v
+}
+
+
+//
+// Look up the value of the dotted key in the config without splitting it.
+//
+func lookupKey(config map[string]interface{}, key string) interface{} {
+	var v interface{} = config
+	for more := true; more; {
+		var k string
+		k, key, more = strings.Cut(key, ".")
+		if v = lookupChild(v, k); v == nil {
+			return nil
+		}
+	}
+	return v
+}
+
+
+//
+// Look up the child value of the key in the map.
+// Maps with non-string keys are matched by the formatted key.
+//
+func lookupChild(v interface{}, k string) interface{} {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m[k]
+	case map[interface{}]interface{}:
+		if child, ok := m[k]; ok {
+			return child
+		}
+		for mk, mv := range m {
+			if fmt.Sprint(mk) == k {
+				return mv
+			}
+		}
+	}
+	return nil
+}
+
+
+//
+// Check if the converted values of the type can be cached.
+// Slices, maps and pointers are converted on every read, since the caller may modify them.
+//
+func isCacheable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Pointer:
+		return false
+	default:
+		return true
+	}
+}
+
+
+//
+// Get the cached value, and the generation to store a value read now.
+// Raw values (a nil type) are never cached.
+//
+func (c *lookupCache) load(k cacheKey, version uint64, root uintptr, settings uint64) (interface{}, uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if k.t == nil || c.version != version || c.root != root || c.settings != settings {
+		return nil, c.gen, false
+	}
+	v, ok := c.values[k]
+	return v, c.gen, ok
+}
+
+
+//
+// Store the value unless the cache was reset after it was read.
+//
+func (c *lookupCache) store(k cacheKey, v interface{}, version uint64, root uintptr, gen, settings uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen || settingsGen.Load() != settings {
+		return
+	}
+	if c.version != version || c.root != root || c.settings != settings || c.values == nil {
+		c.version = version
+		c.root = root
+		c.settings = settings
+		c.values = make(map[cacheKey]interface{})
+	}
+	c.values[k] = v
+}
+
+
+//
+// Drop all cached values.
+// It must be called while the store's mu is held for writing.
+//
+func (c *lookupCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.values = nil
+}
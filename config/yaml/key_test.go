@@ -0,0 +1,166 @@
+//
+// key_test.go
+//
+package yaml_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+	"github.com/k4k3ru-hub/go/config/yaml/yamltest"
+)
+
+
+//
+// Test GetKeyFrom with precompiled keys, and the cache following changes.
+//
+func TestGetKeyFrom(t *testing.T) {
+	store := yamltest.NewString(t, `
+http:
+  timeout: 5s
+  port: 8080
+`)
+	timeoutKey := yaml.Key("http.timeout")
+	portKey := yaml.Key("http.port")
+
+	if v, err := yaml.GetKeyFrom[time.Duration](store, timeoutKey); err != nil || v != 5*time.Second {
+		t.Errorf("Failed to get %s. Expected: 5s, actual: %s, error: %v\n", timeoutKey, v, err)
+	}
+	if v, err := yaml.GetKeyFrom[int](store, portKey); err != nil || v != 8080 {
+		t.Errorf("Failed to get %s. Expected: 8080, actual: %d, error: %v\n", portKey, v, err)
+	}
+
+	store.Set("http.port", 9090)
+	if v, err := yaml.GetKeyFrom[int](store, portKey); err != nil || v != 9090 {
+		t.Errorf("Expected 9090 after Set, actual: %d, error: %v\n", v, err)
+	}
+	store.Replace(map[string]interface{}{"http": map[string]interface{}{"port": 80}})
+	if v := store.GetInt("http.port"); v != 80 {
+		t.Errorf("Expected 80 after Replace, actual: %d\n", v)
+	}
+	if _, err := yaml.GetKeyFrom[time.Duration](store, timeoutKey); err == nil {
+		t.Errorf("Expected an error for the removed key.\n")
+	}
+}
+
+
+//
+// Test keys in maps with non-string keys.
+//
+func TestGetKeyFrom_NonStringKeys(t *testing.T) {
+	store := yamltest.NewString(t, `
+codes:
+  1: one
+  name: codes
+`)
+	if v := store.GetString("codes.1"); v != "one" {
+		t.Errorf("Failed to get codes.1. Expected: one, actual: %s\n", v)
+	}
+	if v := store.GetString("codes.name"); v != "codes" {
+		t.Errorf("Failed to get codes.name. Expected: codes, actual: %s\n", v)
+	}
+}
+
+
+//
+// Test cached reads not allocating.
+//
+func TestGetKeyFrom_Allocs(t *testing.T) {
+	store := yamltest.NewString(t, `
+http:
+  timeout: 5s
+  host: example.com
+`)
+	timeoutKey := yaml.Key("http.timeout")
+	allocs := testing.AllocsPerRun(100, func() {
+		yaml.GetKeyFrom[time.Duration](store, timeoutKey)
+		store.GetString("http.host")
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, actual: %v\n", allocs)
+	}
+}
+
+
+//
+// Benchmark GetString with a dotted key.
+//
+func BenchmarkGetString(b *testing.B) {
+	store := yaml.NewStore(map[string]interface{}{
+		"a": map[string]interface{}{"b": map[string]interface{}{"c": "value"}},
+	})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		store.GetString("a.b.c")
+	}
+}
+
+
+//
+// Benchmark GetKeyFrom with a precompiled key.
+//
+func BenchmarkGetKeyFrom(b *testing.B) {
+	store := yaml.NewStore(map[string]interface{}{
+		"a": map[string]interface{}{"b": map[string]interface{}{"c": "1m30s"}},
+	})
+	k := yaml.Key("a.b.c")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		yaml.GetKeyFrom[time.Duration](store, k)
+	}
+}
+
+
+//
+// Benchmark GetKeyFrom from parallel goroutines.
+//
+func BenchmarkGetKeyFrom_Parallel(b *testing.B) {
+	store := yaml.NewStore(map[string]interface{}{
+		"a": map[string]interface{}{"b": map[string]interface{}{"c": 1}},
+	})
+	k := yaml.Key("a.b.c")
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			yaml.GetKeyFrom[int](store, k)
+		}
+	})
+}
+
+
+//
+// Test cached reads following direct writes to Config and changes of the settings.
+//
+func TestGetKey_Invalidation(t *testing.T) {
+	yaml.Config["invalidation"] = map[string]interface{}{"port": 8080}
+	defer delete(yaml.Config, "invalidation")
+
+	portKey := yaml.Key("invalidation.port")
+	if v, err := yaml.GetKey[int](portKey); err != nil || v != 8080 {
+		t.Fatalf("Failed to get invalidation.port. Actual: %v, error: %v\n", v, err)
+	}
+	yaml.Set("invalidation.port", 9090)
+	if v, err := yaml.GetKey[int](portKey); err != nil || v != 9090 {
+		t.Errorf("Expected 9090 after Set, actual: %v, error: %v\n", v, err)
+	}
+
+	// A new map assigned to Config is seen too.
+	previous := yaml.Config
+	yaml.Config = map[string]interface{}{"invalidation": map[string]interface{}{"port": 6060}}
+	v, err := yaml.GetKey[int](portKey)
+	yaml.Config = previous
+	if err != nil || v != 6060 {
+		t.Errorf("Expected 6060 after assigning Config, actual: %v, error: %v\n", v, err)
+	}
+
+	yaml.Set("invalidation.port", "7070")
+	if _, err := yaml.GetKey[int](portKey); err == nil {
+		t.Errorf("Expected an error for a string without the lenient mode.\n")
+	}
+	yaml.SetLenient(true)
+	defer yaml.SetLenient(false)
+	if v, err := yaml.GetKey[int](portKey); err != nil || v != 7070 {
+		t.Errorf("Expected 7070 in the lenient mode, actual: %v, error: %v\n", v, err)
+	}
+}
@@ -0,0 +1,175 @@
+//
+// lint.go
+//
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+
+var (
+	strictMu sync.RWMutex
+	strict   bool
+)
+
+
+//
+// LintIssue is a duplicate key in a file, or an override changing the type of a key across files.
+// First is where the key was defined first, and Second is where it was defined again.
+//
+type LintIssue struct {
+	Key     string
+	Message string
+	First   Origin
+	Second  Origin
+}
+type LintIssues []*LintIssue
+
+
+//
+// Enable the strict mode.
+// In the strict mode, loading fails if an override changes the type of a key (e.g. a map to a scalar).
+// Duplicate keys in a file always fail to load.
+//
+func SetStrict(enabled bool) {
+	strictMu.Lock()
+	defer strictMu.Unlock()
+	strict = enabled
+}
+
+
+//
+// Report the duplicate keys and the type-changing overrides of the sources without failing on the first one.
+//
+func (l *Loader) Lint(ctx context.Context) (LintIssues, error) {
+	result := newLoadResult()
+	var issues LintIssues
+	for _, src := range l.Sources {
+		srcResult, err := loadSource(ctx, src)
+		if err != nil {
+			var srcIssues LintIssues
+			if errors.As(err, &srcIssues) {
+				issues = append(issues, srcIssues...)
+				continue
+			}
+			return nil, err
+		}
+		result.merge(srcResult)
+	}
+	return append(issues, result.issues...), nil
+}
+
+
+//
+// Get the error message.
+//
+func (i *LintIssue) Error() string {
+	return fmt.Sprintf("%s: %s [%s, %s]", i.Key, i.Message, i.First, i.Second)
+}
+
+
+//
+// Get the error message listing all issues.
+//
+func (e LintIssues) Error() string {
+	messages := make([]string, len(e))
+	for i, issue := range e {
+		messages[i] = issue.Error()
+	}
+	return "Invalid config. (" + strings.Join(messages, ", ") + ")"
+}
+
+
+//
+// Check if the strict mode is enabled.
+//
+func isStrict() bool {
+	strictMu.RLock()
+	defer strictMu.RUnlock()
+	return strict
+}
+
+
+//
+// Find the duplicate keys in the mappings of the document.
+//
+func findDuplicates(doc *yaml.Node, name string) LintIssues {
+	var issues LintIssues
+	var walk func(node *yaml.Node, prefix string)
+	walk = func(node *yaml.Node, prefix string) {
+		switch node.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, child := range node.Content {
+				walk(child, prefix)
+			}
+		case yaml.MappingNode:
+			seen := make(map[string]*yaml.Node)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				keyNode := node.Content[i]
+				key := joinKey(prefix, keyNode.Value)
+				if first, ok := seen[keyNode.Value]; ok && keyNode.Value != "<<" {
+					issues = append(issues, &LintIssue{
+						Key: key,
+						Message: "is defined twice",
+						First: Origin{Source: name, Line: first.Line},
+						Second: Origin{Source: name, Line: keyNode.Line},
+					})
+				} else {
+					seen[keyNode.Value] = keyNode
+				}
+				walk(node.Content[i+1], key)
+			}
+		}
+	}
+	walk(doc, "")
+	return issues
+}
+
+
+//
+// Find the overrides changing the type of a key between maps, lists and scalars.
+//
+func findConflicts(base, override map[string]interface{}, prefix string, baseOrigins, overrideOrigins map[string]Origin, issues *LintIssues) {
+	for k, overrideValue := range override {
+		baseValue, ok := base[k]
+		if !ok || baseValue == nil || overrideValue == nil {
+			continue
+		}
+		key := joinKey(prefix, k)
+		baseKind, overrideKind := valueKind(baseValue), valueKind(overrideValue)
+		if baseKind != overrideKind {
+			*issues = append(*issues, &LintIssue{
+				Key: key,
+				Message: fmt.Sprintf("is overridden from %s to %s", baseKind, overrideKind),
+				First: baseOrigins[key],
+				Second: overrideOrigins[key],
+			})
+			continue
+		}
+		if baseKind == "map" {
+			findConflicts(baseValue.(map[string]interface{}), overrideValue.(map[string]interface{}), key, baseOrigins, overrideOrigins, issues)
+		}
+	}
+}
+
+
+//
+// Get the kind of the config value.
+//
+func valueKind(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "map"
+	case []interface{}:
+		return "list"
+	default:
+		return "scalar"
+	}
+}
@@ -0,0 +1,108 @@
+//
+// lint_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test duplicate keys failing to load with the lines of both definitions.
+//
+func TestDuplicateKeys(t *testing.T) {
+	file, err := createTempYAMLFile(`server:
+  port: 80
+  host: a
+  port: 8080
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	_, err = yaml.NewLoader(yaml.NewFileSource(file)).Load(context.Background())
+	var issues yaml.LintIssues
+	if !errors.As(err, &issues) || len(issues) != 1 {
+		t.Fatalf("Expected 1 lint issue, got %v", err)
+	}
+	issue := issues[0]
+	if issue.Key != "server.port" || issue.First.Line != 2 || issue.Second.Line != 4 || issue.First.Source != file {
+		t.Errorf("Unexpected issue: %+v", issue)
+	}
+}
+
+
+//
+// Test type-changing overrides failing only in the strict mode.
+//
+func TestStrictConflicts(t *testing.T) {
+	base, err := createTempYAMLFile(`server:
+  port: 80
+hosts: [a, b]
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(base)
+	override, err := createTempYAMLFile(`name: api
+server: off
+hosts: [c]
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(override)
+
+	loader := yaml.NewLoader(yaml.NewFileSource(base), yaml.NewFileSource(override))
+	if _, err := loader.Load(context.Background()); err != nil {
+		t.Fatalf("Expected no error without the strict mode, got %v", err)
+	}
+
+	yaml.SetStrict(true)
+	defer yaml.SetStrict(false)
+	_, err = loader.Load(context.Background())
+	var issues yaml.LintIssues
+	if !errors.As(err, &issues) || len(issues) != 1 {
+		t.Fatalf("Expected 1 lint issue, got %v", err)
+	}
+	issue := issues[0]
+	if issue.Key != "server" || issue.First.Source != base || issue.First.Line != 1 || issue.Second.Source != override || issue.Second.Line != 2 {
+		t.Errorf("Unexpected issue: %+v", issue)
+	}
+}
+
+
+//
+// Test Lint collecting the issues of all sources.
+//
+func TestLint(t *testing.T) {
+	dup, err := createTempYAMLFile("a: 1\na: 2\n")
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(dup)
+	base, err := createTempYAMLFile("b:\n  c: 1\n")
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(base)
+
+	issues, err := yaml.NewLoader(
+		yaml.NewFileSource(dup),
+		yaml.NewFileSource(base),
+		yaml.NewMapSource(map[string]interface{}{"b": []interface{}{1}}),
+	).Lint(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to lint: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Key != "a" || issues[1].Key != "b" || issues[1].Second.Source != "memory" {
+		t.Errorf("Unexpected issues: %v", issues)
+	}
+}
@@ -0,0 +1,35 @@
+//
+// node.go
+//
+package yaml
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+
+//
+// Walk all keys of the mapping nodes under the node with their dotted keys.
+// Merge keys ("<<") are skipped.
+//
+func walkNode(node *yaml.Node, prefix string, fn func(key string, keyNode, valueNode *yaml.Node)) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			walkNode(child, prefix, fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			if keyNode.Value == "<<" {
+				continue
+			}
+			key := keyNode.Value
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			fn(key, keyNode, valueNode)
+			walkNode(valueNode, key, fn)
+		}
+	}
+}
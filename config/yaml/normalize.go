@@ -0,0 +1,169 @@
+//
+// normalize.go
+//
+package yaml
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+
+var (
+	normalizeMu     sync.RWMutex
+	normalizeOption *NormalizeOption
+)
+
+
+//
+// NormalizeOption makes keys match regardless of their case or separators.
+// Keys of all sources are normalized when they are loaded, and the keys given to the getters when they are looked up,
+// so "database.Host" in a file, APP_DATABASE__HOST in env and GetString("Database.host") are the same key.
+//
+type NormalizeOption struct {
+	CaseInsensitive bool
+	DashUnderscore  bool
+}
+
+
+//
+// New NormalizeOption with both case-insensitive keys and "-"/"_" equivalence.
+//
+func NewNormalizeOption() *NormalizeOption {
+	return &NormalizeOption{
+		CaseInsensitive: true,
+		DashUnderscore: true,
+	}
+}
+
+
+//
+// Set the key normalization applied to the sources loaded after it.
+// Keys of a source which normalize to the same name fail to load.
+// A nil option disables the normalization.
+//
+func SetNormalizeOption(option *NormalizeOption) {
+	normalizeMu.Lock()
+	defer normalizeMu.Unlock()
+	normalizeOption = option
+	settingsGen.Add(1)
+}
+
+
+//
+// Get the normalization option.
+//
+func getNormalizeOption() *NormalizeOption {
+	normalizeMu.RLock()
+	defer normalizeMu.RUnlock()
+	return normalizeOption
+}
+
+
+//
+// Normalize a key segment.
+// Keys which are already normalized are returned without allocation.
+//
+func (o *NormalizeOption) normalize(key string) string {
+	if o == nil {
+		return key
+	}
+	if o.CaseInsensitive {
+		key = strings.ToLower(key)
+	}
+	if o.DashUnderscore {
+		key = strings.ReplaceAll(key, "-", "_")
+	}
+	return key
+}
+
+
+//
+// Normalize each segment of the path.
+// The path itself is returned if it is already normalized.
+//
+func (o *NormalizeOption) normalizePath(path []string) []string {
+	if o == nil {
+		return path
+	}
+	for i, k := range path {
+		if o.normalize(k) == k {
+			continue
+		}
+		result := make([]string, len(path))
+		copy(result, path[:i])
+		for j := i; j < len(path); j++ {
+			result[j] = o.normalize(path[j])
+		}
+		return result
+	}
+	return path
+}
+
+
+//
+// Normalize the dotted key.
+//
+func (o *NormalizeOption) normalizeKey(key string) string {
+	if o == nil {
+		return key
+	}
+	return strings.Join(o.normalizePath(strings.Split(key, ".")), ".")
+}
+
+
+//
+// Normalize the keys of the loaded result and its origins.
+// Keys normalizing to the same name are reported with the origins of both.
+//
+func (o *NormalizeOption) normalizeResult(result *loadResult) error {
+	var issues LintIssues
+	result.config = o.normalizeMap(result.config, "", "", result.origins, &issues)
+	if len(issues) > 0 {
+		return issues
+	}
+
+	origins := make(map[string]Origin, len(result.origins))
+	for key, origin := range result.origins {
+		origins[o.normalizeKey(key)] = origin
+	}
+	result.origins = origins
+	return nil
+}
+
+
+//
+// Normalize the keys of the map recursively.
+//
+func (o *NormalizeOption) normalizeMap(m map[string]interface{}, rawPrefix, prefix string, origins map[string]Origin, issues *LintIssues) map[string]interface{} {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]interface{}, len(m))
+	seen := make(map[string]string, len(m))
+	for _, k := range keys {
+		normalized := o.normalize(k)
+		if first, ok := seen[normalized]; ok {
+			*issues = append(*issues, &LintIssue{
+				Key: joinKey(prefix, normalized),
+				Message: fmt.Sprintf("is defined as both %s and %s", first, k),
+				First: origins[joinKey(rawPrefix, first)],
+				Second: origins[joinKey(rawPrefix, k)],
+			})
+			continue
+		}
+		seen[normalized] = k
+
+		v := m[k]
+		if child, ok := v.(map[string]interface{}); ok {
+			v = o.normalizeMap(child, joinKey(rawPrefix, k), joinKey(prefix, normalized), origins, issues)
+		}
+		result[normalized] = v
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+//
+// normalize_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test keys of files, env and flags matching after normalization.
+//
+func TestNormalizeOption(t *testing.T) {
+	yaml.SetNormalizeOption(yaml.NewNormalizeOption())
+	defer yaml.SetNormalizeOption(nil)
+
+	file, err := createTempYAMLFile(`Database:
+  Host: db.local
+  max-conns: 10
+  Port: 3306
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+	t.Setenv("NORMTEST_DATABASE__HOST", "db.prod")
+	flags := yaml.NewFlagSource()
+	flags.Set("database.MAX_CONNS=20")
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file), yaml.NewEnvSource("NORMTEST_"), flags); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	if v := store.GetString("database.host"); v != "db.prod" {
+		t.Errorf("Expected db.prod from env, actual: %s\n", v)
+	}
+	if v := store.GetInt("Database.Max-Conns"); v != 20 {
+		t.Errorf("Expected 20 from flags, actual: %d\n", v)
+	}
+	if origin, ok := store.OriginOf("Database.Port"); !ok || origin.Line != 4 {
+		t.Errorf("Unexpected origin of database.port: %v\n", origin)
+	}
+
+	rec := httptest.NewRecorder()
+	yaml.NewDebugHandler(store).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/config?key=Database.Host", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "db.prod") {
+		t.Errorf("Unexpected response: %d %s\n", rec.Code, rec.Body)
+	}
+
+	var changes []yaml.Change
+	unsubscribe := store.Watch("DATABASE.*", func(change yaml.Change) {
+		changes = append(changes, change)
+	})
+	defer unsubscribe()
+	if err := os.WriteFile(file, []byte("Database:\n  Port: 3308\n"), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Failed to reload. Error: %v\n", err)
+	}
+	if len(changes) != 1 || changes[0].Key != "database.port" {
+		t.Errorf("Expected a change of database.port, actual: %+v\n", changes)
+	}
+
+	store.Set("DATABASE.PORT", 3307)
+	if v := store.GetInt("database.port"); v != 3307 {
+		t.Errorf("Expected 3307 after Set, actual: %d\n", v)
+	}
+}
+
+
+//
+// Test keys of a source normalizing to the same name.
+//
+func TestNormalizeOption_Conflict(t *testing.T) {
+	yaml.SetNormalizeOption(yaml.NewNormalizeOption())
+	defer yaml.SetNormalizeOption(nil)
+
+	file, err := createTempYAMLFile(`server:
+  max-conns: 10
+  max_conns: 20
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	_, err = yaml.NewLoader(yaml.NewFileSource(file)).Load(context.Background())
+	var issues yaml.LintIssues
+	if !errors.As(err, &issues) || len(issues) != 1 {
+		t.Fatalf("Expected 1 lint issue, actual: %v\n", err)
+	}
+	if issues[0].Key != "server.max_conns" || issues[0].First.Line != 2 || issues[0].Second.Line != 3 {
+		t.Errorf("Unexpected issue: %+v\n", issues[0])
+	}
+}
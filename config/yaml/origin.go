@@ -0,0 +1,140 @@
+//
+// origin.go
+//
+package yaml
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+
+//
+// Origin is where a key was defined.
+//
+type Origin struct {
+	Source string
+	Line   int
+}
+
+
+//
+// Get the origin of the key.
+//
+func OriginOf(key string) (Origin, bool) {
+	return std.OriginOf(key)
+}
+
+
+//
+// Get the origin of the key.
+// The key is normalized by the NormalizeOption.
+//
+func (s *Store) OriginOf(key string) (Origin, bool) {
+	key = getNormalizeOption().normalizeKey(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	origin, ok := s.origins[key]
+	return origin, ok
+}
+
+
+//
+// Get the origin as "source:line".
+//
+func (o Origin) String() string {
+	if o.Line == 0 {
+		return o.Source
+	}
+	return o.Source + ":" + strconv.Itoa(o.Line)
+}
+
+
+//
+// Load the source with the origin of each key.
+// Keys are normalized if SetNormalizeOption is set.
+//
+func loadSource(ctx context.Context, src Source) (*loadResult, error) {
+	var result *loadResult
+	if ds, ok := src.(detailedSource); ok {
+		var err error
+		if result, err = ds.loadDetailed(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		config, err := src.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = newLoadResult()
+		result.config = config
+		origin := Origin{Source: describeSource(src)}
+		walkConfig(config, "", func(key string, value interface{}) {
+			result.origins[key] = origin
+		})
+	}
+
+	if option := getNormalizeOption(); option != nil {
+		if err := option.normalizeResult(result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+
+//
+// Get the name of the source used in origins.
+//
+func describeSource(src Source) string {
+	switch s := src.(type) {
+	case *EnvSource:
+		return "env:" + s.Prefix
+	case *FlagSource:
+		return "flag"
+	case *MapSource:
+		return "memory"
+	default:
+		return fmt.Sprintf("%T", src)
+	}
+}
+
+
+//
+// Walk all keys of the config with their dotted keys.
+//
+func walkConfig(config map[string]interface{}, prefix string, fn func(key string, value interface{})) {
+	for k, v := range config {
+		key := joinKey(prefix, k)
+		fn(key, v)
+		if child, ok := v.(map[string]interface{}); ok {
+			walkConfig(child, key, fn)
+		}
+	}
+}
+
+
+//
+// Remove the origins of the keys which no longer exist in the config.
+//
+func pruneOrigins(origins map[string]Origin, config map[string]interface{}) {
+	for key := range origins {
+		var v interface{} = config
+		for _, k := range strings.Split(key, ".") {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				v = nil
+				break
+			}
+			if v, ok = m[k]; !ok {
+				v = nil
+				break
+			}
+		}
+		if v == nil {
+			delete(origins, key)
+		}
+	}
+}
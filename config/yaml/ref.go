@@ -0,0 +1,226 @@
+//
+// ref.go
+//
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+
+var (
+	refPattern = regexp.MustCompile(`\$\{ref:([^}]+)\}`)
+)
+
+
+type refResolver struct {
+	config   map[string]interface{}
+	resolved map[string]bool
+	secrets  map[string]bool
+	stack    []string
+}
+
+
+//
+// Resolve the ${ref:path.to.key} references against the merged config.
+// A value which is a single reference keeps the type of the referenced value,
+// and references inside a longer string are concatenated as strings.
+// The keys of the values derived from references to secrets, or to sensitive key names, are recorded in the secrets.
+//
+func resolveRefs(config map[string]interface{}, secrets map[string]bool) error {
+	r := &refResolver{
+		config: config,
+		resolved: make(map[string]bool),
+		secrets: secrets,
+	}
+	for k := range config {
+		if err := r.resolveKey(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+
+//
+// Resolve the references in the value of the key.
+//
+func (r *refResolver) resolveKey(key string) error {
+	if r.resolved[key] {
+		return nil
+	}
+	for i, k := range r.stack {
+		if k == key {
+			return fmt.Errorf("Circular config reference. (%s)", strings.Join(append(r.stack[i:], key), " -> "))
+		}
+	}
+	r.stack = append(r.stack, key)
+	defer func() {
+		r.stack = r.stack[:len(r.stack)-1]
+	}()
+
+	keys := strings.Split(key, ".")
+	value := lookupConfig(r.config, keys)
+	if value != nil {
+		resolved, err := r.resolveValue(value, key)
+		if err != nil {
+			return err
+		}
+		setValue(r.config, keys, resolved)
+	}
+	r.resolved[key] = true
+	return nil
+}
+
+
+//
+// Resolve the references in the value.
+//
+func (r *refResolver) resolveValue(value interface{}, key string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return r.resolveString(v, key)
+	case map[string]interface{}:
+		for k := range v {
+			if err := r.resolveKey(key + "." + k); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case []interface{}:
+		return r.resolveList(v, key)
+	default:
+		return v, nil
+	}
+}
+
+
+//
+// Resolve the references in the items of the list.
+// The items are not addressable by references, so maps in them are resolved directly with indexed keys.
+//
+func (r *refResolver) resolveList(list []interface{}, key string) ([]interface{}, error) {
+	for i, item := range list {
+		resolved, err := r.resolveItem(item, joinKey(key, strconv.Itoa(i)))
+		if err != nil {
+			return nil, err
+		}
+		list[i] = resolved
+	}
+	return list, nil
+}
+
+
+//
+// Resolve the references in the value of a list item.
+//
+func (r *refResolver) resolveItem(value interface{}, key string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			resolved, err := r.resolveItem(item, key + "." + k)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = resolved
+		}
+		return v, nil
+	case []interface{}:
+		return r.resolveList(v, key)
+	default:
+		return r.resolveValue(v, key)
+	}
+}
+
+
+//
+// Resolve the references in the string.
+//
+func (r *refResolver) resolveString(s, key string) (interface{}, error) {
+	matches := refPattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	// The whole value is a reference.
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		target, err := r.target(s[matches[0][2]:matches[0][3]], key)
+		if err != nil {
+			return nil, err
+		}
+		return copyValue(target), nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(s[last:m[0]])
+		target, err := r.target(s[m[2]:m[3]], key)
+		if err != nil {
+			return nil, err
+		}
+		formatted, ok := formatScalar(reflect.ValueOf(target))
+		if !ok {
+			return nil, fmt.Errorf("Cannot concatenate a non-scalar config reference. (key: %s, ref: %s)", key, s[m[2]:m[3]])
+		}
+		b.WriteString(formatted)
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
+
+
+//
+// Resolve the referenced key, and return its value.
+//
+func (r *refResolver) target(ref, key string) (interface{}, error) {
+	ref = getNormalizeOption().normalizeKey(strings.TrimSpace(ref))
+	if err := r.resolveKey(ref); err != nil {
+		return nil, err
+	}
+	target := lookupConfig(r.config, strings.Split(ref, "."))
+	if target == nil {
+		return nil, fmt.Errorf("Unknown config reference. (key: %s, ref: %s)", key, ref)
+	}
+	if r.isSecret(ref) {
+		r.secrets[key] = true
+	}
+	return target, nil
+}
+
+
+//
+// Check if the referenced key is a secret, contains a secret, or has a sensitive name.
+//
+func (r *refResolver) isSecret(ref string) bool {
+	if isSensitiveName(ref[strings.LastIndex(ref, ".")+1:], DefaultRedactKeys) {
+		return true
+	}
+	for k := range r.secrets {
+		if k == ref || strings.HasPrefix(k, ref + ".") {
+			return true
+		}
+	}
+	return false
+}
+
+
+//
+// Look up the value of the keys in the config.
+//
+func lookupConfig(config map[string]interface{}, keys []string) interface{} {
+	var v interface{} = config
+	for _, k := range keys {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
@@ -0,0 +1,108 @@
+//
+// ref_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test resolving references across sources.
+//
+func TestInitSources_Ref(t *testing.T) {
+	store := yaml.NewStore(nil)
+	err := store.InitSources(context.Background(),
+		yaml.NewMapSource(map[string]interface{}{
+			"db": map[string]interface{}{
+				"host": "${ref:hosts.primary}",
+				"port": "${ref:ports.db}",
+				"dsn":  "tcp(${ref:db.host}:${ref:db.port})",
+			},
+			"hosts": map[string]interface{}{"primary": "old-host"},
+			"ports": map[string]interface{}{"db": 3306},
+		}),
+		yaml.NewMapSource(map[string]interface{}{
+			"hosts": map[string]interface{}{"primary": "db.local"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+
+	if val := store.GetString("db.host"); val != "db.local" {
+		t.Errorf("Failed to read db.host value. Expected: db.local, actual: %s\n", val)
+	}
+	if val, err := yaml.GetFrom[int](store, "db.port"); err != nil || val != 3306 {
+		t.Errorf("Failed to read db.port as int. Actual: %v, error: %v\n", val, err)
+	}
+	if val := store.GetString("db.dsn"); val != "tcp(db.local:3306)" {
+		t.Errorf("Failed to read db.dsn value. Expected: tcp(db.local:3306), actual: %s\n", val)
+	}
+}
+
+
+//
+// Test circular and unknown references.
+//
+func TestInitSources_RefError(t *testing.T) {
+	store := yaml.NewStore(nil)
+	err := store.InitSources(context.Background(), yaml.NewMapSource(map[string]interface{}{
+		"a": "${ref:b}",
+		"b": "x-${ref:a}",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "Circular") {
+		t.Errorf("Expected a circular reference error, actual: %v\n", err)
+	}
+
+	err = store.InitSources(context.Background(), yaml.NewMapSource(map[string]interface{}{
+		"a": "${ref:missing.key}",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "Unknown") {
+		t.Errorf("Expected an unknown reference error, actual: %v\n", err)
+	}
+}
+
+
+//
+// Test resolving references in maps inside lists.
+//
+func TestInitSources_RefInList(t *testing.T) {
+	store := yaml.NewStore(nil)
+	err := store.InitSources(context.Background(), yaml.NewMapSource(map[string]interface{}{
+		"host": "db.local",
+		"servers": []interface{}{
+			map[string]interface{}{"addr": "${ref:host}:3306"},
+			map[string]interface{}{"tags": []interface{}{"${ref:host}"}},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+
+	var servers []struct {
+		Addr string   `yaml:"addr"`
+		Tags []string `yaml:"tags"`
+	}
+	if err := store.Decode("servers", &servers); err != nil {
+		t.Fatalf("Failed to decode. Error: %v\n", err)
+	}
+	if len(servers) != 2 || servers[0].Addr != "db.local:3306" || len(servers[1].Tags) != 1 || servers[1].Tags[0] != "db.local" {
+		t.Errorf("Unexpected servers: %+v\n", servers)
+	}
+
+	err = store.InitSources(context.Background(), yaml.NewMapSource(map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{"addr": "a"},
+			map[string]interface{}{"addr": "${ref:missing}"},
+		},
+	}))
+	if err == nil || !strings.Contains(err.Error(), "servers.1.addr") {
+		t.Errorf("Expected an unknown reference error with the indexed key, actual: %v\n", err)
+	}
+}
@@ -0,0 +1,86 @@
+//
+// reload.go
+//
+package yaml
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+
+//
+// Register a callback which is called after every successful reload.
+//
+func (s *Store) OnReload(callback func()) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	s.reloadCallbacks = append(s.reloadCallbacks, callback)
+}
+
+
+//
+// Reload the config from the sources given to Init.
+// The current config is kept if any of them fails to load.
+//
+func (s *Store) Reload() error {
+	return s.reload(context.Background())
+}
+
+
+//
+// Watch the sources given to Init, and reload the config when any of them changed.
+// Watching stops when the context is done.
+//
+func (s *Store) StartWatching(ctx context.Context) error {
+	s.mu.RLock()
+	l := s.loader
+	s.mu.RUnlock()
+	if l == nil {
+		return fmt.Errorf("Need to call Init before watching.")
+	}
+
+	l.Watch(ctx, func() {
+		if err := s.reload(ctx); err != nil {
+			log.Printf("[WARN] Failed to reload config. (error: %s)\n", err)
+		}
+	})
+	return nil
+}
+
+
+//
+// Reload the config and call the callbacks.
+//
+func (s *Store) reload(ctx context.Context) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	s.mu.RLock()
+	l := s.loader
+	s.mu.RUnlock()
+	if l == nil {
+		return fmt.Errorf("Need to call Init before reloading.")
+	}
+
+	result, err := l.load(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.prepare(result); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	previous := *s.config
+	s.activate(result)
+	version := s.version
+	s.mu.Unlock()
+
+	for _, callback := range s.reloadCallbacks {
+		callback()
+	}
+	s.notifyChanges(previous, version)
+	return nil
+}
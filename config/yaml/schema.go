@@ -0,0 +1,235 @@
+//
+// schema.go
+//
+package yaml
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+
+//
+// Validate the merged config against the JSON Schema file written in YAML or JSON.
+// A subset of draft 2020-12 is supported:
+// type, required, enum, pattern, minimum, maximum, properties, additionalProperties and items.
+//
+func ValidateSchema(schemaPath string) error {
+	return std.ValidateSchema(schemaPath)
+}
+
+
+//
+// Validate the merged config against the JSON Schema file written in YAML or JSON.
+//
+func (s *Store) ValidateSchema(schemaPath string) error {
+	bytes, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return err
+	}
+	var schema map[string]interface{}
+	if err := yaml.Unmarshal(bytes, &schema); err != nil {
+		return fmt.Errorf("Failed to parse schema. (path: %s, error: %s)", schemaPath, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var errs ValidationErrors
+	validateSchema(schema, *s.config, "", &errs)
+	for _, e := range errs {
+		// Missing keys are reported at the origin of the nearest parent.
+		for k := e.Key; k != ""; {
+			if origin, ok := s.origins[k]; ok {
+				e.Origin = &origin
+				break
+			}
+			i := strings.LastIndex(k, ".")
+			if i < 0 {
+				break
+			}
+			k = k[:i]
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+
+//
+// Validate the value against the schema recursively.
+//
+func validateSchema(schema map[string]interface{}, value interface{}, key string, errs *ValidationErrors) {
+	fail := func(rule, format string, args ...interface{}) {
+		*errs = append(*errs, &ValidationError{Key: key, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if t, ok := schema["type"]; ok {
+		var types []string
+		switch tt := t.(type) {
+		case string:
+			types = []string{tt}
+		case []interface{}:
+			for _, v := range tt {
+				types = append(types, fmt.Sprint(v))
+			}
+		}
+		matched := false
+		for _, name := range types {
+			if schemaTypeMatches(name, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("type", "must be %s", strings.Join(types, " or "))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		matched := false
+		for _, option := range enum {
+			if schemaEqual(option, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("enum", "must be one of %v", enum)
+		}
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if s, ok := value.(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fail("pattern", "has an invalid pattern: %s", pattern)
+			} else if !re.MatchString(s) {
+				fail("pattern", "must match %s", pattern)
+			}
+		}
+	}
+
+	if n, ok := schemaNumber(value); ok {
+		if minimum, ok := schemaNumber(schema["minimum"]); ok && n < minimum {
+			fail("minimum", "must be >= %v", schema["minimum"])
+		}
+		if maximum, ok := schemaNumber(schema["maximum"]); ok && n > maximum {
+			fail("maximum", "must be <= %v", schema["maximum"])
+		}
+	}
+
+	if object, ok := value.(map[string]interface{}); ok {
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := object[fmt.Sprint(name)]; !ok {
+					*errs = append(*errs, &ValidationError{Key: joinKey(key, fmt.Sprint(name)), Rule: "required", Message: "is required"})
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			childKey := joinKey(key, name)
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				validateSchema(property, object[name], childKey, errs)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					*errs = append(*errs, &ValidationError{Key: childKey, Rule: "additionalProperties", Message: "is not allowed"})
+				}
+			case map[string]interface{}:
+				validateSchema(additional, object[name], childKey, errs)
+			}
+		}
+	}
+
+	if items, ok := value.([]interface{}); ok {
+		if itemSchema, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range items {
+				validateSchema(itemSchema, item, joinKey(key, strconv.Itoa(i)), errs)
+			}
+		}
+	}
+}
+
+
+//
+// Check if the value matches the JSON Schema type.
+//
+func schemaTypeMatches(name string, value interface{}) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := schemaNumber(value)
+		return ok
+	case "integer":
+		n, ok := schemaNumber(value)
+		return ok && n == math.Trunc(n)
+	default:
+		return false
+	}
+}
+
+
+//
+// Get the number as float64.
+//
+func schemaNumber(value interface{}) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+
+//
+// Check if the two values are equal as JSON values.
+//
+func schemaEqual(a, b interface{}) bool {
+	if an, ok := schemaNumber(a); ok {
+		bn, ok := schemaNumber(b)
+		return ok && an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,85 @@
+//
+// schema_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test ValidateSchema reporting violations with dotted keys and lines.
+//
+func TestValidateSchema(t *testing.T) {
+	file, err := createTempYAMLFile(`server:
+  port: 70000
+  mode: fast
+  name: API
+  extra: true
+hosts:
+  - a
+  - 1
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	schema, err := createTempYAMLFile(`{
+  "type": "object",
+  "properties": {
+    "server": {
+      "type": "object",
+      "required": ["port", "tls"],
+      "additionalProperties": false,
+      "properties": {
+        "port": {"type": "integer", "minimum": 1, "maximum": 65535},
+        "mode": {"enum": ["debug", "release"]},
+        "name": {"type": "string", "pattern": "^[a-z]+$"}
+      }
+    },
+    "hosts": {"type": "array", "items": {"type": "string"}}
+  }
+}`)
+	if err != nil {
+		t.Fatalf("Failed to create schema file: %v", err)
+	}
+	defer os.Remove(schema)
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+
+	var errs yaml.ValidationErrors
+	if !errors.As(store.ValidateSchema(schema), &errs) {
+		t.Fatalf("Expected ValidationErrors.\n")
+	}
+	want := map[string]int{
+		"server.port":  2,
+		"server.mode":  3,
+		"server.name":  4,
+		"server.extra": 5,
+		"server.tls":   1,
+		"hosts.1":      0,
+	}
+	if len(errs) != len(want) {
+		t.Errorf("Expected %d errors, actual: %v\n", len(want), errs)
+	}
+	for _, e := range errs {
+		line, ok := want[e.Key]
+		if !ok {
+			t.Errorf("Unexpected error: %v\n", e)
+			continue
+		}
+		if line > 0 && (e.Origin == nil || e.Origin.Line != line) {
+			t.Errorf("Unexpected origin. (key: %s, expected line: %d, actual: %v)\n", e.Key, line, e.Origin)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+//
+// snapshot.go
+//
+package yaml
+
+import (
+	"fmt"
+)
+
+
+const (
+	DefaultHistorySize = 10
+)
+
+
+//
+// Snapshot is a version of the loaded config.
+//
+type Snapshot struct {
+	Metadata
+	Version uint64
+
+	config  map[string]interface{}
+	origins map[string]Origin
+	secrets map[string]bool
+}
+
+
+//
+// Register a validator which checks a candidate config before it is activated.
+// If any validator fails on reload, the store keeps running on the last good config.
+//
+func AddValidator(validator func(candidate *Store) error) {
+	std.AddValidator(validator)
+}
+
+
+//
+// Get the snapshots from the oldest to the newest.
+//
+func History() []*Snapshot {
+	return std.History()
+}
+
+
+//
+// Activate the snapshot of the version again.
+//
+func Rollback(version uint64) error {
+	return std.Rollback(version)
+}
+
+
+//
+// Set the number of snapshots kept in the history.
+//
+func SetHistorySize(size int) {
+	std.SetHistorySize(size)
+}
+
+
+//
+// Register a validator which checks a candidate config before it is activated.
+//
+func (s *Store) AddValidator(validator func(candidate *Store) error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	s.validators = append(s.validators, validator)
+}
+
+
+//
+// Get the snapshots from the oldest to the newest.
+//
+func (s *Store) History() []*Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*Snapshot(nil), s.snapshots...)
+}
+
+
+//
+// Activate the snapshot of the version again, and call the reload callbacks.
+//
+func (s *Store) Rollback(version uint64) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	s.mu.Lock()
+	var target *Snapshot
+	for _, snapshot := range s.snapshots {
+		if snapshot.Version == version {
+			target = snapshot
+			break
+		}
+	}
+	if target == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("Snapshot not found. (version: %d)", version)
+	}
+	previous := *s.config
+	*s.config = copyConfig(target.config)
+	s.origins = target.origins
+	s.secrets = target.secrets
+	s.changedKeys = nil
+	s.current = target
+	s.cache.reset()
+	s.mu.Unlock()
+
+	for _, callback := range s.reloadCallbacks {
+		callback()
+	}
+	s.notifyChanges(previous, version)
+	return nil
+}
+
+
+//
+// Set the number of snapshots kept in the history.
+//
+func (s *Store) SetHistorySize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.historySize = size
+	s.trimHistory()
+}
+
+
+//
+// Get the current version.
+// It is 0 before the first load.
+//
+func (s *Store) Version() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.current == nil {
+		return 0
+	}
+	return s.current.Version
+}
+
+
+//
+// Get a copy of the config of the snapshot.
+//
+func (s *Snapshot) Config() map[string]interface{} {
+	return copyConfig(s.config)
+}
+
+
+//
+// Apply the aliases, decrypt the values, resolve the references, and run the validators on the candidate result.
+// It must be called while reloadMu is held.
+//
+func (s *Store) prepare(result *loadResult) error {
+	applyAliases(result)
+	if result.secrets == nil {
+		result.secrets = make(map[string]bool)
+	}
+	if err := decryptValues(result.config, result.secrets); err != nil {
+		return err
+	}
+	if err := resolveRefs(result.config, result.secrets); err != nil {
+		return err
+	}
+	if len(s.validators) == 0 {
+		return nil
+	}
+	candidate := NewStore(copyConfig(result.config))
+	candidate.origins = result.origins
+	for _, validator := range s.validators {
+		if err := validator(candidate); err != nil {
+			return fmt.Errorf("Config was rejected by a validator. (error: %s)", err)
+		}
+	}
+	return nil
+}
+
+
+//
+// Activate the result as a new snapshot.
+// It must be called while mu is held.
+//
+func (s *Store) activate(result *loadResult) {
+	s.version++
+	snapshot := &Snapshot{
+		Metadata: newMetadata(result.digests),
+		Version: s.version,
+		config: copyConfig(result.config),
+		origins: result.origins,
+		secrets: result.secrets,
+	}
+	s.snapshots = append(s.snapshots, snapshot)
+	s.trimHistory()
+
+	*s.config = result.config
+	s.origins = result.origins
+	s.secrets = result.secrets
+	s.changedKeys = nil
+	s.current = snapshot
+	s.cache.reset()
+}
+
+
+//
+// Drop the oldest snapshots over the history size.
+//
+func (s *Store) trimHistory() {
+	size := s.historySize
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	if len(s.snapshots) > size {
+		s.snapshots = append([]*Snapshot(nil), s.snapshots[len(s.snapshots)-size:]...)
+	}
+}
@@ -0,0 +1,76 @@
+//
+// snapshot_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test rejecting an invalid reload, and rolling back to a previous snapshot.
+//
+func TestStore_Rollback(t *testing.T) {
+	file, err := createTempYAMLFile("port: 8080\n")
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	store := yaml.NewStore(nil)
+	store.AddValidator(func(candidate *yaml.Store) error {
+		if port := candidate.GetInt("port"); port <= 0 {
+			return fmt.Errorf("invalid port: %d", port)
+		}
+		return nil
+	})
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+
+	// Invalid config is not activated.
+	if err := os.WriteFile(file, []byte("port: 0\n"), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	if err := store.Reload(); err == nil {
+		t.Errorf("Expected a validation error.\n")
+	}
+	if val := store.GetInt("port"); val != 8080 || store.Version() != 1 {
+		t.Errorf("Failed to keep the last good config. (port: %d, version: %d)\n", val, store.Version())
+	}
+
+	if err := os.WriteFile(file, []byte("port: 9090\n"), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Failed to reload. Error: %v\n", err)
+	}
+	if history := store.History(); len(history) != 2 || history[1].Version != 2 {
+		t.Errorf("Unexpected history: %v\n", history)
+	}
+
+	// Values and origins set after the snapshot are rolled back too.
+	store.Set("port", 7070)
+	if err := store.Rollback(2); err != nil {
+		t.Fatalf("Failed to roll back. Error: %v\n", err)
+	}
+	if origin, ok := store.OriginOf("port"); !ok || origin.Source != file || store.GetInt("port") != 9090 {
+		t.Errorf("Failed to roll back the set value. (port: %d, origin: %v)\n", store.GetInt("port"), origin)
+	}
+
+	if err := store.Rollback(1); err != nil {
+		t.Fatalf("Failed to roll back. Error: %v\n", err)
+	}
+	if val := store.GetInt("port"); val != 8080 || store.Version() != 1 {
+		t.Errorf("Failed to roll back. (port: %d, version: %d)\n", val, store.Version())
+	}
+	if err := store.Rollback(100); err == nil {
+		t.Errorf("Expected an error for an unknown version.\n")
+	}
+}
@@ -0,0 +1,471 @@
+//
+// source.go
+//
+package yaml
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+
+const (
+	DefaultEnvSeparator = "__"
+)
+
+
+var (
+	sourceFactoriesMu sync.RWMutex
+	sourceFactories   = map[string]func(path string) (Source, error){
+		"http":  func(path string) (Source, error) { return NewHTTPSource(path), nil },
+		"https": func(path string) (Source, error) { return NewHTTPSource(path), nil },
+	}
+
+	extraSourcesMu sync.Mutex
+	extraSources   []Source
+)
+
+
+//
+// Source provides a config tree from a backend.
+//
+type Source interface {
+	Load(ctx context.Context) (map[string]interface{}, error)
+}
+
+
+//
+// Watcher is optionally implemented by a Source which can detect its own changes.
+// Watch blocks until the context is done, and calls notify every time the source changed.
+//
+type Watcher interface {
+	Watch(ctx context.Context, notify func()) error
+}
+
+
+//
+// Loader merges the sources in the declared order.
+//
+type Loader struct {
+	Sources []Source
+}
+
+
+//
+// detailedSource is implemented by sources which know the line of each key or verify their files.
+//
+type detailedSource interface {
+	loadDetailed(ctx context.Context) (*loadResult, error)
+}
+
+
+//
+// loadResult is a loaded config with the origin of each key and the verified digest of each file.
+//
+type loadResult struct {
+	config  map[string]interface{}
+	origins map[string]Origin
+	digests map[string]string
+	issues  LintIssues
+	secrets map[string]bool
+}
+
+
+type EnvSource struct {
+	Prefix    string
+	Separator string
+}
+type FileSource struct {
+	Path string
+}
+type FlagSource struct {
+	values []string
+}
+type MapSource struct {
+	Config map[string]interface{}
+}
+
+
+//
+// New Loader
+//
+func NewLoader(sources ...Source) *Loader {
+	return &Loader{
+		Sources: sources,
+	}
+}
+
+
+//
+// New EnvSource
+// APP_DATABASE__HOST is loaded as database.host with the prefix "APP_".
+//
+func NewEnvSource(prefix string) *EnvSource {
+	return &EnvSource{
+		Prefix: prefix,
+		Separator: DefaultEnvSeparator,
+	}
+}
+
+
+//
+// New FileSource
+//
+func NewFileSource(path string) *FileSource {
+	return &FileSource{
+		Path: path,
+	}
+}
+
+
+//
+// New FlagSource
+// Register it to a flag set with flag.Var to accept "-set key=value" options.
+//
+func NewFlagSource() *FlagSource {
+	return &FlagSource{}
+}
+
+
+//
+// New MapSource
+//
+func NewMapSource(config map[string]interface{}) *MapSource {
+	return &MapSource{
+		Config: config,
+	}
+}
+
+
+//
+// New Source from the path given to -yaml.
+// A path with a registered scheme ("scheme://...") is passed to its factory, otherwise it is loaded as a file.
+//
+func NewSource(path string) (Source, error) {
+	i := strings.Index(path, "://")
+	if i < 0 {
+		return NewFileSource(path), nil
+	}
+
+	scheme := path[:i]
+	sourceFactoriesMu.RLock()
+	factory, ok := sourceFactories[scheme]
+	sourceFactoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Unknown config source scheme. (scheme: %s, path: %s)", scheme, path)
+	}
+	return factory(path)
+}
+
+
+//
+// Register a factory for the -yaml paths starting with "scheme://".
+//
+func RegisterSource(scheme string, factory func(path string) (Source, error)) {
+	sourceFactoriesMu.Lock()
+	defer sourceFactoriesMu.Unlock()
+	sourceFactories[scheme] = factory
+}
+
+
+//
+// Add a source which Init merges after the -yaml files.
+//
+func AddSource(src Source) {
+	extraSourcesMu.Lock()
+	defer extraSourcesMu.Unlock()
+	extraSources = append(extraSources, src)
+}
+
+
+//
+// New loadResult
+//
+func newLoadResult() *loadResult {
+	return &loadResult{
+		config: make(map[string]interface{}),
+		origins: make(map[string]Origin),
+		digests: make(map[string]string),
+		secrets: make(map[string]bool),
+	}
+}
+
+
+//
+// Load and merge all sources.
+//
+func (l *Loader) Load(ctx context.Context) (map[string]interface{}, error) {
+	result, err := l.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.config, nil
+}
+
+
+//
+// Load and merge all sources with the origin of each key.
+//
+func (l *Loader) load(ctx context.Context) (*loadResult, error) {
+	result := newLoadResult()
+	for _, src := range l.Sources {
+		srcResult, err := loadSource(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		result.merge(srcResult)
+	}
+	if isStrict() && len(result.issues) > 0 {
+		return nil, result.issues
+	}
+	pruneOrigins(result.origins, result.config)
+	return result, nil
+}
+
+
+//
+// Merge the other result into the result.
+//
+func (r *loadResult) merge(other *loadResult) {
+	r.issues = append(r.issues, other.issues...)
+	findConflicts(r.config, other.config, "", r.origins, other.origins, &r.issues)
+	mergeConfig(r.config, other.config)
+	for k, origin := range other.origins {
+		r.origins[k] = origin
+	}
+	for path, digest := range other.digests {
+		r.digests[path] = digest
+	}
+	for key := range other.secrets {
+		r.secrets[key] = true
+	}
+}
+
+
+//
+// Start watching the sources which implement Watcher.
+//
+func (l *Loader) Watch(ctx context.Context, notify func()) {
+	for _, src := range l.Sources {
+		w, ok := src.(Watcher)
+		if !ok {
+			continue
+		}
+		go func(w Watcher) {
+			if err := w.Watch(ctx, notify); err != nil && ctx.Err() == nil {
+				log.Printf("[WARN] Failed to watch config source. (error: %s)\n", err)
+			}
+		}(w)
+	}
+}
+
+
+//
+// Load environment variables.
+//
+func (s *EnvSource) Load(ctx context.Context) (map[string]interface{}, error) {
+	separator := s.Separator
+	if separator == "" {
+		separator = DefaultEnvSeparator
+	}
+
+	result := make(map[string]interface{})
+	for _, env := range os.Environ() {
+		name, value, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(name, s.Prefix) {
+			continue
+		}
+		name = strings.TrimPrefix(name, s.Prefix)
+		if name == "" {
+			continue
+		}
+		setValue(result, strings.Split(strings.ToLower(name), separator), value)
+	}
+	return result, nil
+}
+
+
+//
+// Load the YAML file.
+//
+func (s *FileSource) Load(ctx context.Context) (map[string]interface{}, error) {
+	result, err := s.loadDetailed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.config, nil
+}
+
+
+//
+// Load and verify the YAML file with the line of each key.
+//
+func (s *FileSource) loadDetailed(ctx context.Context) (*loadResult, error) {
+	bytes, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := verifyFile(os.ReadFile, s.Path, bytes)
+	if err != nil {
+		return nil, err
+	}
+	result, err := unmarshalConfig(bytes, s.Path)
+	if err != nil {
+		return nil, err
+	}
+	if digest != "" {
+		result.digests[s.Path] = digest
+	}
+	return result, nil
+}
+
+
+//
+// Load the flag values.
+// Each value is parsed as YAML, so "-set port=8080" is loaded as an int.
+//
+func (s *FlagSource) Load(ctx context.Context) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for _, kv := range s.values {
+		key, raw, _ := strings.Cut(kv, "=")
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		setValue(result, strings.Split(key, "."), value)
+	}
+	return result, nil
+}
+
+
+//
+// Get the flag values. (flag.Value interface)
+//
+func (s *FlagSource) String() string {
+	return strings.Join(s.values, ",")
+}
+
+
+//
+// Add a flag value. (flag.Value interface)
+//
+func (s *FlagSource) Set(value string) error {
+	if key, _, ok := strings.Cut(value, "="); !ok || key == "" {
+		return fmt.Errorf("Need key=value format. (value: %s)", value)
+	}
+	s.values = append(s.values, value)
+	return nil
+}
+
+
+//
+// Load a copy of the map.
+//
+func (s *MapSource) Load(ctx context.Context) (map[string]interface{}, error) {
+	return copyConfig(s.Config), nil
+}
+
+
+//
+// Unmarshal YAML bytes to config with the line of each key.
+// All documents separated by "---" are merged in order, except the ones skipped by SelectDocuments.
+// The name is the file path or URL used in warnings and origins.
+//
+func unmarshalConfig(data []byte, name string) (*loadResult, error) {
+	result := newLoadResult()
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		if issues := findDuplicates(&doc, name); len(issues) > 0 {
+			return nil, issues
+		}
+
+		docResult := newLoadResult()
+		if err := doc.Decode(&docResult.config); err != nil {
+			return nil, err
+		}
+		if !selectDocument(docResult.config) {
+			continue
+		}
+		warnDeprecated(&doc, name)
+		walkNode(&doc, "", func(key string, keyNode, valueNode *yaml.Node) {
+			docResult.origins[key] = Origin{Source: name, Line: keyNode.Line}
+		})
+		result.merge(docResult)
+	}
+	return result, nil
+}
+
+
+//
+// Set the value to the nested keys, creating maps on the way.
+//
+func setValue(config map[string]interface{}, keys []string, value interface{}) {
+	for _, k := range keys[:len(keys)-1] {
+		child, ok := config[k].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			config[k] = child
+		}
+		config = child
+	}
+	config[keys[len(keys)-1]] = value
+}
+
+
+//
+// Deep copy the config.
+//
+func copyConfig(config map[string]interface{}) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		result[k] = copyValue(v)
+	}
+	return result
+}
+
+
+//
+// Deep copy the config value.
+//
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return copyConfig(v)
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, vv := range v {
+			result[i] = copyValue(vv)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[interface{}]interface{}, len(v))
+		for k, vv := range v {
+			result[k] = copyValue(vv)
+		}
+		return result
+	default:
+		return v
+	}
+}
@@ -0,0 +1,83 @@
+//
+// source_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test Loader merging sources in the declared order.
+//
+func TestLoader_Load(t *testing.T) {
+	t.Setenv("TESTAPP_DATABASE__HOST", "env-host")
+
+	file, err := createTempYAMLFile(`
+database:
+  host: file-host
+  port: 3306
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	flags := yaml.NewFlagSource()
+	if err := flags.Set("database.port=3307"); err != nil {
+		t.Fatalf("Failed to set flag value: %v", err)
+	}
+
+	loader := yaml.NewLoader(
+		yaml.NewMapSource(map[string]interface{}{"name": "memory"}),
+		yaml.NewFileSource(file),
+		yaml.NewEnvSource("TESTAPP_"),
+		flags,
+	)
+	config, err := loader.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+
+	database, _ := config["database"].(map[string]interface{})
+	if val, ok := database["host"].(string); !ok || val != "env-host" {
+		t.Errorf("Failed to read database.host value. Expected: env-host, actual: %v\n", database["host"])
+	}
+	if val, ok := database["port"].(int); !ok || val != 3307 {
+		t.Errorf("Failed to read database.port value. Expected: 3307, actual: %v\n", database["port"])
+	}
+	if val, ok := config["name"].(string); !ok || val != "memory" {
+		t.Errorf("Failed to read name value. Expected: memory, actual: %v\n", config["name"])
+	}
+}
+
+
+//
+// Test Init with a registered source scheme.
+//
+func TestInit_RegisteredSource(t *testing.T) {
+	yaml.RegisterSource("mem", func(path string) (yaml.Source, error) {
+		return yaml.NewMapSource(map[string]interface{}{"key1": path}), nil
+	})
+
+	os.Args = []string{"cmd", "-yaml", "mem://test", "-set", "key2=override"}
+	if err := yaml.Init(); err != nil {
+		t.Fatalf("Failed to execute Init. Error: %v\n", err)
+	}
+	if val := yaml.GetString("key1"); val != "mem://test" {
+		t.Errorf("Failed to read key1 value. Expected: mem://test, actual: %s\n", val)
+	}
+	if val := yaml.GetString("key2"); val != "override" {
+		t.Errorf("Failed to read key2 value. Expected: override, actual: %s\n", val)
+	}
+
+	os.Args = []string{"cmd", "-yaml", "unknown://test"}
+	if err := yaml.Init(); err == nil {
+		t.Errorf("Expected an error for an unknown scheme.\n")
+	}
+}
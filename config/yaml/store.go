@@ -0,0 +1,218 @@
+//
+// store.go
+//
+package yaml
+
+import (
+	"context"
+	"sync"
+)
+
+
+//
+// Store holds a config tree.
+// The package level functions use the default store whose tree is Config,
+// and instances created by NewStore are independent of it.
+//
+type Store struct {
+	mu          sync.RWMutex
+	config      *map[string]interface{}
+	origins     map[string]Origin
+	secrets     map[string]bool
+	loader      *Loader
+	changedKeys []string
+	cache       lookupCache
+
+	current     *Snapshot
+	snapshots   []*Snapshot
+	version     uint64
+	historySize int
+
+	reloadMu        sync.Mutex
+	reloadCallbacks []func()
+	validators      []func(candidate *Store) error
+
+	watchMu       sync.Mutex
+	subscriptions []*subscription
+}
+
+
+//
+// New Store
+//
+func NewStore(config map[string]interface{}) *Store {
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+	return &Store{
+		config: &config,
+	}
+}
+
+
+//
+// New default store which holds Config.
+//
+func newDefaultStore() *Store {
+	return &Store{
+		config: &Config,
+	}
+}
+
+
+//
+// Get the default store.
+//
+func Default() *Store {
+	return std
+}
+
+
+//
+// Initialize from the sources in order.
+//
+func InitSources(ctx context.Context, sources ...Source) error {
+	return std.InitSources(ctx, sources...)
+}
+
+
+//
+// Register a callback which is called after every successful reload.
+//
+func OnReload(callback func()) {
+	std.OnReload(callback)
+}
+
+
+//
+// Reload the config from the sources given to Init.
+//
+func Reload() error {
+	return std.Reload()
+}
+
+
+//
+// Watch the sources given to Init, and reload the config when any of them changed.
+//
+func StartWatching(ctx context.Context) error {
+	return std.StartWatching(ctx)
+}
+
+
+//
+// Set the value of the key.
+//
+func Set(key string, value interface{}) {
+	std.Set(key, value)
+}
+
+
+//
+// Save the values changed by Set to the YAML file.
+//
+func Save(path string) error {
+	return std.Save(path)
+}
+
+
+//
+// Get boolean value from key.
+//
+func GetBool(key string) bool {
+	return std.GetBool(key)
+}
+
+
+//
+// Get string value from key.
+//
+func GetString(key string) string {
+	return std.GetString(key)
+}
+
+
+//
+// Get int value from key.
+//
+func GetInt(key string) int {
+	return std.GetInt(key)
+}
+
+
+//
+// Get int64 value from key.
+//
+func GetInt64(key string) int64 {
+	return std.GetInt64(key)
+}
+
+
+//
+// Get float64 value from key.
+//
+func GetFloat64(key string) float64 {
+	return std.GetFloat64(key)
+}
+
+
+//
+// Get array value from key.
+//
+func GetArray(key string) []interface{} {
+	return std.GetArray(key)
+}
+
+
+//
+// Get array int value from key.
+//
+func GetArrayInt(key string) []int {
+	return std.GetArrayInt(key)
+}
+
+
+//
+// Get array string value from key.
+//
+func GetArrayString(key string) []string {
+	return std.GetArrayString(key)
+}
+
+
+//
+// Initialize from the sources in order.
+//
+func (s *Store) InitSources(ctx context.Context, sources ...Source) error {
+	return s.initLoader(ctx, NewLoader(sources...))
+}
+
+
+//
+// Get the current config tree.
+// The returned map must not be modified.
+//
+func (s *Store) Config() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return *s.config
+}
+
+
+//
+// Replace the config tree, and return the previous one.
+//
+func (s *Store) Replace(config map[string]interface{}) map[string]interface{} {
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := *s.config
+	*s.config = config
+	s.origins = nil
+	s.secrets = nil
+	s.changedKeys = nil
+	s.cache.reset()
+	return previous
+}
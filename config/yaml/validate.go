@@ -0,0 +1,333 @@
+//
+// validate.go
+//
+package yaml
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+
+type ValidationError struct {
+	Key     string
+	Rule    string
+	Message string
+	Origin  *Origin
+}
+type ValidationErrors []*ValidationError
+
+
+//
+// Validate the struct by the validate tags of its fields.
+// Supported rules are required, nonempty, min=N, max=N, oneof=a b c, url and hostname_port.
+// Every failing field is reported with its dotted key.
+//
+//   Port int    `yaml:"port" validate:"required,min=1,max=65535"`
+//   Mode string `yaml:"mode" validate:"oneof=debug info warn"`
+//
+func Validate(v interface{}) error {
+	return validateWithPrefix(v, "")
+}
+
+
+//
+// Get the error message.
+//
+func (e *ValidationError) Error() string {
+	if e.Origin != nil {
+		return e.Key + ": " + e.Message + " [" + e.Origin.String() + "]"
+	}
+	return e.Key + ": " + e.Message
+}
+
+
+//
+// Get the error message listing all errors.
+//
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "Invalid config. (" + strings.Join(messages, ", ") + ")"
+}
+
+
+//
+// Validate the value with the key prefix.
+// Without the decoded config, zero fields are treated as unset.
+//
+func validateWithPrefix(v interface{}, prefix string) error {
+	return validate(v, validateNode{}, prefix)
+}
+
+
+//
+// Validate the value decoded from the config value with the key prefix.
+// Fields are unset only if their keys are missing or null in the config.
+//
+func validateDecoded(v, config interface{}, prefix string) error {
+	return validate(v, validateNode{value: config, known: true}, prefix)
+}
+
+
+//
+// validateNode is the config value decoded into the validated value.
+// It is not known when the value was not decoded from the config.
+//
+type validateNode struct {
+	value interface{}
+	known bool
+}
+
+
+//
+// Validate the value, and return the validation errors.
+//
+func validate(v interface{}, node validateNode, prefix string) error {
+	var errs ValidationErrors
+	validateValue(reflect.ValueOf(v), node, prefix, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+
+//
+// Get the config value of the child, or the zero node if the value is not known.
+//
+func (n validateNode) child(k interface{}) validateNode {
+	if !n.known {
+		return n
+	}
+	switch m := n.value.(type) {
+	case map[string]interface{}:
+		if name, ok := k.(string); ok {
+			return validateNode{value: lookupField(m, name), known: true}
+		}
+		return validateNode{value: m[fmt.Sprint(k)], known: true}
+	case map[interface{}]interface{}:
+		return validateNode{value: m[k], known: true}
+	case []interface{}:
+		if i, ok := k.(int); ok && i < len(m) {
+			return validateNode{value: m[i], known: true}
+		}
+	}
+	return validateNode{known: true}
+}
+
+
+//
+// Validate the fields of the struct recursively.
+//
+func validateValue(v reflect.Value, node validateNode, key string, errs *ValidationErrors) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, inline := fieldKey(field)
+			if name == "-" {
+				continue
+			}
+			fieldKey, fieldNode := key, node
+			if !inline {
+				fieldKey, fieldNode = joinKey(key, name), node.child(name)
+			}
+			fv := v.Field(i)
+			if tag := field.Tag.Get("validate"); tag != "" {
+				unset := fv.IsZero()
+				if fieldNode.known {
+					unset = fieldNode.value == nil
+				}
+				validateRules(fv, fieldKey, tag, unset, errs)
+			}
+			validateValue(fv, fieldNode, fieldKey, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), node.child(i), joinKey(key, strconv.Itoa(i)), errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			validateValue(iter.Value(), node.child(iter.Key().Interface()), joinKey(key, fmt.Sprint(iter.Key().Interface())), errs)
+		}
+	}
+}
+
+
+//
+// Validate the field by the rules of the tag.
+// Unset fields are only checked by required and nonempty.
+//
+func validateRules(v reflect.Value, key, tag string, unset bool, errs *ValidationErrors) {
+	rules := strings.Split(tag, ",")
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if name == "" {
+			continue
+		}
+		// Optional fields are only checked when they are set.
+		if unset && name != "required" && name != "nonempty" {
+			continue
+		}
+		if message := checkRule(v, name, param); message != "" {
+			*errs = append(*errs, &ValidationError{Key: key, Rule: name, Message: message})
+		}
+	}
+}
+
+
+//
+// Check the rule, and return the error message if it fails.
+//
+func checkRule(v reflect.Value, name, param string) string {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			if name == "required" || name == "nonempty" {
+				return "is required"
+			}
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch name {
+	case "required":
+		if v.IsZero() {
+			return "is required"
+		}
+	case "nonempty":
+		switch v.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			if v.Len() == 0 {
+				return "must not be empty"
+			}
+		default:
+			if v.IsZero() {
+				return "must not be empty"
+			}
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Sprintf("has an invalid %s rule: %s", name, param)
+		}
+		n, isLength, ok := measure(v)
+		if !ok {
+			return fmt.Sprintf("does not support the %s rule", name)
+		}
+		subject := "must be"
+		if isLength {
+			subject = "length must be"
+		}
+		if name == "min" && n < limit {
+			return fmt.Sprintf("%s >= %s", subject, param)
+		}
+		if name == "max" && n > limit {
+			return fmt.Sprintf("%s <= %s", subject, param)
+		}
+	case "oneof":
+		s, ok := formatScalar(v)
+		if !ok {
+			return "does not support the oneof rule"
+		}
+		for _, option := range strings.Fields(param) {
+			if s == option {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of [%s]", param)
+	case "url":
+		s, ok := formatScalar(v)
+		if !ok {
+			return "does not support the url rule"
+		}
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be a URL"
+		}
+	case "hostname_port":
+		s, ok := formatScalar(v)
+		if !ok {
+			return "does not support the hostname_port rule"
+		}
+		host, port, err := net.SplitHostPort(s)
+		if err != nil || host == "" {
+			return "must be host:port"
+		}
+		if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+			return "must be host:port"
+		}
+	default:
+		return fmt.Sprintf("has an unknown rule: %s", name)
+	}
+	return ""
+}
+
+
+//
+// Get the number or the length of the value.
+//
+func measure(v reflect.Value) (float64, bool, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	case reflect.String:
+		return float64(len([]rune(v.String()))), true, true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), true, true
+	default:
+		return 0, false, false
+	}
+}
+
+
+//
+// Get the key of the struct field in the same way as yaml.v3.
+//
+func fieldKey(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	name, options, _ := strings.Cut(tag, ",")
+	inline := false
+	for _, option := range strings.Split(options, ",") {
+		if option == "inline" {
+			inline = true
+		}
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, inline
+}
+
+
+//
+// Join the dotted key.
+//
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
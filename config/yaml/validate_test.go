@@ -0,0 +1,114 @@
+//
+// validate_test.go
+//
+package yaml_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+	"github.com/k4k3ru-hub/go/config/yaml/yamltest"
+)
+
+
+type testServerConfig struct {
+	Port     int      `yaml:"port" validate:"required,min=1,max=65535"`
+	LogLevel string   `yaml:"log_level" validate:"oneof=debug info warn"`
+	Endpoint string   `yaml:"endpoint" validate:"url"`
+	Upstream string   `yaml:"upstream" validate:"hostname_port"`
+	Hosts    []string `yaml:"hosts" validate:"nonempty"`
+	Name     string   `yaml:"name" validate:"required"`
+}
+
+
+//
+// Test Decode reporting every failing key.
+//
+func TestDecode_Validate(t *testing.T) {
+	store := yamltest.NewString(t, `
+server:
+  port: 70000
+  log_level: trace
+  endpoint: not-a-url
+  upstream: localhost
+  hosts: []
+`)
+
+	var config testServerConfig
+	err := store.Decode("server", &config)
+
+	var errs yaml.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, actual: %v\n", err)
+	}
+	want := map[string]string{
+		"server.port":      "max",
+		"server.log_level": "oneof",
+		"server.endpoint":  "url",
+		"server.upstream":  "hostname_port",
+		"server.hosts":     "nonempty",
+		"server.name":      "required",
+	}
+	if len(errs) != len(want) {
+		t.Errorf("Expected %d errors, actual: %v\n", len(want), errs)
+	}
+	for _, e := range errs {
+		if want[e.Key] != e.Rule {
+			t.Errorf("Unexpected error. (key: %s, rule: %s)\n", e.Key, e.Rule)
+		}
+	}
+}
+
+
+//
+// Test Decode with a valid config.
+//
+func TestDecode_Valid(t *testing.T) {
+	store := yamltest.NewString(t, `
+server:
+  port: 8080
+  endpoint: https://example.com/api
+  upstream: db.local:3306
+  hosts: [a]
+  name: api
+`)
+
+	var config testServerConfig
+	if err := store.Decode("server", &config); err != nil {
+		t.Fatalf("Failed to decode. Error: %v\n", err)
+	}
+	if config.Port != 8080 || config.Name != "api" {
+		t.Errorf("Failed to decode values. Actual: %+v\n", config)
+	}
+}
+
+
+//
+// Test rules checking explicit zero values, and skipping missing keys.
+//
+func TestDecode_ValidateZero(t *testing.T) {
+	type workerConfig struct {
+		Workers int    `yaml:"workers" validate:"min=1"`
+		Mode    string `yaml:"mode" validate:"oneof=fast slow"`
+	}
+
+	store := yamltest.NewString(t, `
+jobs:
+  workers: 0
+`)
+	var c workerConfig
+	err := store.Decode("jobs", &c)
+	var errs yaml.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Key != "jobs.workers" || errs[0].Rule != "min" {
+		t.Errorf("Expected a min error for jobs.workers only, actual: %v\n", err)
+	}
+
+	store = yamltest.NewString(t, `
+jobs:
+  mode: fast
+`)
+	if err := store.Decode("jobs", &c); err != nil {
+		t.Errorf("Expected no error for a missing key, actual: %v\n", err)
+	}
+}
@@ -0,0 +1,105 @@
+//
+// value.go
+//
+package yaml
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+
+//
+// Value is a config value bound to a key.
+// Get always returns the value of the current config, so it follows reloads without callbacks.
+//
+type Value[T any] struct {
+	store      *Store
+	key        *KeyPath
+	validators []func(T) error
+	last       atomic.Pointer[T]
+}
+
+
+//
+// Bind the key of the default config.
+// The value must exist, be convertible to T and pass the validators, and reloads breaking them are rejected.
+//
+//   timeout, err := yaml.Bind[time.Duration]("http.timeout", func(d time.Duration) error {
+//       if d <= 0 {
+//           return fmt.Errorf("must be positive")
+//       }
+//       return nil
+//   })
+//   ...
+//   client.Timeout = timeout.Get()
+//
+func Bind[T any](key string, validators ...func(T) error) (*Value[T], error) {
+	return BindTo[T](std, key, validators...)
+}
+
+
+//
+// Bind the key of the store.
+//
+func BindTo[T any](s *Store, key string, validators ...func(T) error) (*Value[T], error) {
+	v := &Value[T]{
+		store: s,
+		key: Key(key),
+		validators: validators,
+	}
+	current, err := v.check(s)
+	if err != nil {
+		return nil, err
+	}
+	v.last.Store(&current)
+
+	s.AddValidator(func(candidate *Store) error {
+		_, err := v.check(candidate)
+		return err
+	})
+	s.OnReload(func() {
+		if current, err := v.check(s); err == nil {
+			v.last.Store(&current)
+		}
+	})
+	return v, nil
+}
+
+
+//
+// Get the current value.
+// If the key was removed, cannot be converted or fails the validators after Set or Replace,
+// the last valid value is returned.
+//
+func (v *Value[T]) Get() T {
+	if current, err := v.check(v.store); err == nil {
+		return current
+	}
+	return *v.last.Load()
+}
+
+
+//
+// Get the bound key.
+//
+func (v *Value[T]) Key() string {
+	return v.key.String()
+}
+
+
+//
+// Get the value from the store, and run the validators.
+//
+func (v *Value[T]) check(s *Store) (T, error) {
+	current, err := GetKeyFrom[T](s, v.key)
+	if err != nil {
+		return current, err
+	}
+	for _, validator := range v.validators {
+		if err := validator(current); err != nil {
+			return current, fmt.Errorf("Invalid config value. (key: %s, error: %s)", v.key, err)
+		}
+	}
+	return current, nil
+}
@@ -0,0 +1,91 @@
+//
+// value_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test a bound value following reloads and rejecting invalid ones.
+//
+func TestBindTo(t *testing.T) {
+	file, err := createTempYAMLFile("http:\n  timeout: 5s\n")
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	positive := func(d time.Duration) error {
+		if d <= 0 {
+			return fmt.Errorf("must be positive")
+		}
+		return nil
+	}
+	timeout, err := yaml.BindTo[time.Duration](store, "http.timeout", positive)
+	if err != nil {
+		t.Fatalf("Failed to bind. Error: %v\n", err)
+	}
+	if v := timeout.Get(); v != 5*time.Second {
+		t.Errorf("Expected 5s, actual: %s\n", v)
+	}
+
+	if err := os.WriteFile(file, []byte("http:\n  timeout: 10s\n"), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Failed to reload. Error: %v\n", err)
+	}
+	if v := timeout.Get(); v != 10*time.Second {
+		t.Errorf("Expected 10s after reload, actual: %s\n", v)
+	}
+
+	// A reload breaking the bound value is rejected.
+	if err := os.WriteFile(file, []byte("http:\n  timeout: -1s\n"), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	if err := store.Reload(); err == nil {
+		t.Errorf("Expected a validation error.\n")
+	}
+	if v := timeout.Get(); v != 10*time.Second {
+		t.Errorf("Expected 10s after the rejected reload, actual: %s\n", v)
+	}
+
+	// The last valid value is kept if Set breaks the bound value.
+	store.Set("http.timeout", "-1s")
+	if v := timeout.Get(); v != 10*time.Second {
+		t.Errorf("Expected 10s after an invalid Set, actual: %s\n", v)
+	}
+
+	// The last valid value is kept if the key is removed.
+	store.Replace(nil)
+	if v := timeout.Get(); v != 10*time.Second {
+		t.Errorf("Expected the last valid value, actual: %s\n", v)
+	}
+}
+
+
+//
+// Test binding a missing or invalid value.
+//
+func TestBindTo_Invalid(t *testing.T) {
+	store := yaml.NewStore(map[string]interface{}{"timeout": "soon"})
+	if _, err := yaml.BindTo[time.Duration](store, "timeout"); err == nil {
+		t.Errorf("Expected an error for an invalid duration.\n")
+	}
+	if _, err := yaml.BindTo[time.Duration](store, "missing"); err == nil {
+		t.Errorf("Expected an error for a missing key.\n")
+	}
+}
@@ -0,0 +1,161 @@
+//
+// verify.go
+//
+package yaml
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+
+const (
+	DefaultChecksumSuffix  = ".sha256"
+	DefaultSignatureSuffix = ".sig"
+)
+
+
+var (
+	verifyMu     sync.RWMutex
+	verifyOption *VerifyOption
+)
+
+
+//
+// Metadata of the loaded config.
+// Digests holds the verified "sha256:<hex>" digest of each file when verification is enabled.
+//
+type Metadata struct {
+	LoadedAt time.Time
+	Digests  map[string]string
+}
+
+
+//
+// VerifyOption enables the integrity verification of config files.
+// With Checksum, each file must have a sidecar file (config.yaml.sha256) in the sha256sum format.
+// With PublicKey, each file must have an Ed25519 signature file (config.yaml.sig) in raw or base64.
+// For HTTPSource, the sidecar files are fetched from the URL with the suffix.
+//
+type VerifyOption struct {
+	Checksum        bool
+	ChecksumSuffix  string
+	PublicKey       ed25519.PublicKey
+	SignatureSuffix string
+}
+
+
+//
+// New VerifyOption
+//
+func NewVerifyOption() *VerifyOption {
+	return &VerifyOption{
+		ChecksumSuffix: DefaultChecksumSuffix,
+		SignatureSuffix: DefaultSignatureSuffix,
+	}
+}
+
+
+//
+// Set the option to verify every loaded file.
+// Files which fail the verification are refused on both load and reload.
+// Set nil to disable the verification.
+//
+func SetVerifyOption(option *VerifyOption) {
+	verifyMu.Lock()
+	defer verifyMu.Unlock()
+	verifyOption = option
+}
+
+
+//
+// Get the metadata of the loaded config.
+//
+func GetMetadata() Metadata {
+	return std.Metadata()
+}
+
+
+//
+// Get the metadata of the loaded config.
+//
+func (s *Store) Metadata() Metadata {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.current == nil {
+		return Metadata{}
+	}
+	return s.current.Metadata
+}
+
+
+//
+// New Metadata
+//
+func newMetadata(digests map[string]string) Metadata {
+	return Metadata{
+		LoadedAt: time.Now(),
+		Digests: digests,
+	}
+}
+
+
+//
+// Verify the file data by its sidecar files, and return its digest.
+// The digest is empty if the verification is disabled.
+//
+func verifyFile(readFile func(name string) ([]byte, error), path string, data []byte) (string, error) {
+	verifyMu.RLock()
+	option := verifyOption
+	verifyMu.RUnlock()
+	if option == nil || (!option.Checksum && option.PublicKey == nil) {
+		return "", nil
+	}
+
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+
+	if option.Checksum {
+		suffix := option.ChecksumSuffix
+		if suffix == "" {
+			suffix = DefaultChecksumSuffix
+		}
+		sidecar, err := readFile(path + suffix)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read the checksum file. (path: %s, error: %s)", path, err)
+		}
+		fields := strings.Fields(string(sidecar))
+		if len(fields) == 0 || !strings.EqualFold(fields[0], digest) {
+			return "", fmt.Errorf("Config file does not match the checksum. (path: %s)", path)
+		}
+	}
+
+	if option.PublicKey != nil {
+		suffix := option.SignatureSuffix
+		if suffix == "" {
+			suffix = DefaultSignatureSuffix
+		}
+		sig, err := readFile(path + suffix)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read the signature file. (path: %s, error: %s)", path, err)
+		}
+		if len(sig) != ed25519.SignatureSize {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+			if err != nil {
+				return "", fmt.Errorf("Failed to decode the signature file. (path: %s, error: %s)", path, err)
+			}
+			sig = decoded
+		}
+		if !ed25519.Verify(option.PublicKey, data, sig) {
+			return "", fmt.Errorf("Config file does not match the signature. (path: %s)", path)
+		}
+	}
+
+	return "sha256:" + digest, nil
+}
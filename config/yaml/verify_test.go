@@ -0,0 +1,98 @@
+//
+// verify_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test verifying files by sidecar checksums.
+//
+func TestSetVerifyOption_Checksum(t *testing.T) {
+	content := []byte("key1: value1\n")
+	file, err := createTempYAMLFile(string(content))
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	sum := sha256.Sum256(content)
+	if err := os.WriteFile(file + ".sha256", []byte(hex.EncodeToString(sum[:]) + "  config.yaml\n"), 0600); err != nil {
+		t.Fatalf("Failed to create checksum file: %v", err)
+	}
+	defer os.Remove(file + ".sha256")
+
+	option := yaml.NewVerifyOption()
+	option.Checksum = true
+	yaml.SetVerifyOption(option)
+	defer yaml.SetVerifyOption(nil)
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	if digest := store.Metadata().Digests[file]; digest != "sha256:" + hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected digest: %s\n", digest)
+	}
+
+	// A tampered file must be refused on reload.
+	if err := os.WriteFile(file, []byte("key1: tampered\n"), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	if err := store.Reload(); err == nil {
+		t.Errorf("Expected an error for a tampered file.\n")
+	}
+	if val := store.GetString("key1"); val != "value1" {
+		t.Errorf("Failed to keep key1 value. Expected: value1, actual: %s\n", val)
+	}
+}
+
+
+//
+// Test verifying files by Ed25519 signatures.
+//
+func TestSetVerifyOption_Signature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	content := []byte("key1: value1\n")
+	file, err := createTempYAMLFile(string(content))
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, content))
+	if err := os.WriteFile(file + ".sig", []byte(sig), 0600); err != nil {
+		t.Fatalf("Failed to create signature file: %v", err)
+	}
+	defer os.Remove(file + ".sig")
+
+	option := yaml.NewVerifyOption()
+	option.PublicKey = publicKey
+	yaml.SetVerifyOption(option)
+	defer yaml.SetVerifyOption(nil)
+
+	if err := yaml.NewStore(nil).InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Errorf("Failed to load a signed file. Error: %v\n", err)
+	}
+
+	otherKey, _, _ := ed25519.GenerateKey(nil)
+	option.PublicKey = otherKey
+	if err := yaml.NewStore(nil).InitSources(context.Background(), yaml.NewFileSource(file)); err == nil {
+		t.Errorf("Expected an error for a signature by another key.\n")
+	}
+}
@@ -0,0 +1,158 @@
+//
+// watch.go
+//
+package yaml
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+
+//
+// Change is a key changed by a reload or rollback.
+// Old is nil for an added key, and New is nil for a removed key.
+// The values are shared with the config, and must not be modified.
+//
+type Change struct {
+	Key     string
+	Old     interface{}
+	New     interface{}
+	Version uint64
+}
+
+
+//
+// subscription is a callback registered by Watch.
+//
+type subscription struct {
+	prefix   string
+	callback func(Change)
+	closed   atomic.Bool
+}
+
+
+//
+// Watch the keys under the prefix of the default config.
+//
+func Watch(prefix string, callback func(Change)) (unsubscribe func()) {
+	return std.Watch(prefix, callback)
+}
+
+
+//
+// Watch the keys under the prefix, and call the callback for each key changed by a reload or rollback.
+// "cache" and "cache.*" match cache.size and cache.ttl.ttl, and an empty prefix matches all keys.
+// The prefix is normalized by the NormalizeOption set at the time.
+// Changes are delivered in the key order after the reload callbacks, and never concurrently,
+// so the callback must not reload the store.
+// The returned function unsubscribes the callback.
+//
+func (s *Store) Watch(prefix string, callback func(Change)) (unsubscribe func()) {
+	sub := &subscription{
+		prefix: getNormalizeOption().normalizeKey(strings.TrimSuffix(strings.TrimSuffix(prefix, "*"), ".")),
+		callback: callback,
+	}
+	s.watchMu.Lock()
+	s.subscriptions = append(s.subscriptions, sub)
+	s.watchMu.Unlock()
+
+	return func() {
+		sub.closed.Store(true)
+		s.watchMu.Lock()
+		defer s.watchMu.Unlock()
+		for i, registered := range s.subscriptions {
+			if registered == sub {
+				s.subscriptions = append(s.subscriptions[:i:i], s.subscriptions[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+
+//
+// Get the changed leaf keys between the configs in the key order.
+//
+func Diff(oldConfig, newConfig map[string]interface{}) []Change {
+	return diffConfig(oldConfig, newConfig, 0)
+}
+
+
+//
+// Deliver the changes from the previous config to the current one to the subscriptions.
+// It must be called while reloadMu is held.
+//
+func (s *Store) notifyChanges(previous map[string]interface{}, version uint64) {
+	s.watchMu.Lock()
+	subscriptions := append([]*subscription(nil), s.subscriptions...)
+	s.watchMu.Unlock()
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	s.mu.RLock()
+	changes := diffConfig(previous, *s.config, version)
+	s.mu.RUnlock()
+	for _, sub := range subscriptions {
+		for _, change := range changes {
+			if sub.closed.Load() {
+				break
+			}
+			if sub.matches(change.Key) {
+				sub.callback(change)
+			}
+		}
+	}
+}
+
+
+//
+// Check if the key is under the prefix.
+//
+func (sub *subscription) matches(key string) bool {
+	return sub.prefix == "" || key == sub.prefix || strings.HasPrefix(key, sub.prefix + ".")
+}
+
+
+//
+// Get the changed leaf keys between the configs in the key order.
+//
+func diffConfig(oldConfig, newConfig map[string]interface{}, version uint64) []Change {
+	oldValues := flattenConfig(oldConfig)
+	newValues := flattenConfig(newConfig)
+
+	var changes []Change
+	for key, oldValue := range oldValues {
+		newValue, ok := newValues[key]
+		if !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, Change{Key: key, Old: oldValue, New: newValue, Version: version})
+		}
+	}
+	for key, newValue := range newValues {
+		if _, ok := oldValues[key]; !ok {
+			changes = append(changes, Change{Key: key, New: newValue, Version: version})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+
+//
+// Get the leaf values of the config by their dotted keys.
+//
+func flattenConfig(config map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	walkConfig(config, "", func(key string, value interface{}) {
+		if m, ok := value.(map[string]interface{}); ok && len(m) > 0 {
+			return
+		}
+		values[key] = value
+	})
+	return values
+}
@@ -0,0 +1,85 @@
+//
+// watch_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test Watch delivering only the changes under the prefix, and unsubscribing.
+//
+func TestStore_Watch(t *testing.T) {
+	file, err := createTempYAMLFile(`
+cache:
+  size: 100
+  ttl: 1m
+server:
+  port: 8080
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	var changes []yaml.Change
+	unsubscribe := store.Watch("cache.*", func(change yaml.Change) {
+		changes = append(changes, change)
+	})
+
+	if err := os.WriteFile(file, []byte(`
+cache:
+  size: 200
+  shards: 4
+server:
+  port: 9090
+`), 0600); err != nil {
+		t.Fatalf("Failed to write YAML file: %v", err)
+	}
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Failed to reload. Error: %v\n", err)
+	}
+
+	want := []yaml.Change{
+		{Key: "cache.shards", New: 4, Version: 2},
+		{Key: "cache.size", Old: 100, New: 200, Version: 2},
+		{Key: "cache.ttl", Old: "1m", Version: 2},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Expected %d changes, actual: %v\n", len(want), changes)
+	}
+	for i, change := range changes {
+		if change != want[i] {
+			t.Errorf("Unexpected change. Expected: %v, actual: %v\n", want[i], change)
+		}
+	}
+
+	// Rolling back delivers the reverse changes.
+	changes = nil
+	if err := store.Rollback(1); err != nil {
+		t.Fatalf("Failed to roll back. Error: %v\n", err)
+	}
+	if len(changes) != 3 || changes[1].Key != "cache.size" || changes[1].New != 100 {
+		t.Errorf("Unexpected changes on rollback: %v\n", changes)
+	}
+
+	changes = nil
+	unsubscribe()
+	unsubscribe()
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Failed to reload. Error: %v\n", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Expected no changes after unsubscribing, actual: %v\n", changes)
+	}
+}
@@ -0,0 +1,198 @@
+//
+// write.go
+//
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+
+//
+// Set the value of the key.
+// The change is kept in memory until Save is called, and dropped when the config is reloaded, rolled back or replaced.
+// The tree and the origins are copied on write, so maps returned by Config and the snapshots are not modified.
+//
+func (s *Store) Set(key string, value interface{}) {
+	key = getNormalizeOption().normalizeKey(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	config := copyConfig(*s.config)
+	setValue(config, strings.Split(key, "."), value)
+	*s.config = config
+	s.cache.reset()
+	origins := make(map[string]Origin, len(s.origins)+1)
+	for k, origin := range s.origins {
+		origins[k] = origin
+	}
+	origins[key] = Origin{Source: "set"}
+	s.origins = origins
+	for _, k := range s.changedKeys {
+		if k == key {
+			return
+		}
+	}
+	s.changedKeys = append(s.changedKeys, key)
+}
+
+
+//
+// Save the values changed by Set to the YAML file.
+// The file is edited through yaml.Node, so comments, key order and anchors are preserved.
+// In a multi-document file, each key is set in the last document defining it, or in the first document.
+// It is written to a temporary file and renamed, so readers never see a partial file.
+//
+func (s *Store) Save(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	perm := os.FileMode(0644)
+	var docs []*yaml.Node
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		for {
+			var doc yaml.Node
+			if err := decoder.Decode(&doc); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return fmt.Errorf("Failed to parse YAML. (path: %s, error: %s)", path, err)
+			}
+			docs = append(docs, &doc)
+		}
+		if info, err := os.Stat(path); err == nil {
+			perm = info.Mode().Perm()
+		}
+	case os.IsNotExist(err):
+	default:
+		return err
+	}
+	if len(docs) == 0 {
+		docs = append(docs, &yaml.Node{Kind: yaml.DocumentNode})
+	}
+	for _, doc := range docs {
+		if len(doc.Content) == 0 {
+			doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+		}
+	}
+
+	for _, key := range s.changedKeys {
+		keys := strings.Split(key, ".")
+		value := lookupConfig(*s.config, keys)
+		if value == nil {
+			// The key no longer exists in the config.
+			continue
+		}
+		doc := docs[0]
+		for _, d := range docs {
+			if findNodeValue(d.Content[0], keys) != nil {
+				doc = d
+			}
+		}
+		if err := setNodeValue(doc.Content[0], keys, value); err != nil {
+			return fmt.Errorf("%s (path: %s, key: %s)", err, path, key)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, doc := range docs {
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, buf.Bytes(), perm); err != nil {
+		return err
+	}
+	s.changedKeys = nil
+	return nil
+}
+
+
+//
+// Find the value node of the nested keys in the mapping node.
+//
+func findNodeValue(node *yaml.Node, keys []string) *yaml.Node {
+	option := getNormalizeOption()
+	for _, k := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var valueNode *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if option.normalize(node.Content[j].Value) == k {
+				valueNode = node.Content[j+1]
+				break
+			}
+		}
+		if valueNode == nil {
+			return nil
+		}
+		node = valueNode
+	}
+	return node
+}
+
+
+//
+// Set the value to the nested keys of the mapping node, creating mappings on the way.
+//
+func setNodeValue(node *yaml.Node, keys []string, value interface{}) error {
+	for i, k := range keys {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("Cannot set a key under a non-mapping value.")
+		}
+
+		var valueNode *yaml.Node
+		option := getNormalizeOption()
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if option.normalize(node.Content[j].Value) == k {
+				valueNode = node.Content[j+1]
+				break
+			}
+		}
+
+		// Last key.
+		if i == len(keys)-1 {
+			var newNode yaml.Node
+			if err := newNode.Encode(value); err != nil {
+				return err
+			}
+			if valueNode == nil {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, &newNode)
+				return nil
+			}
+			newNode.Anchor = valueNode.Anchor
+			newNode.HeadComment = valueNode.HeadComment
+			newNode.LineComment = valueNode.LineComment
+			newNode.FootComment = valueNode.FootComment
+			if newNode.Tag == valueNode.Tag {
+				newNode.Style = valueNode.Style
+			}
+			*valueNode = newNode
+			return nil
+		}
+
+		if valueNode == nil {
+			valueNode = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, valueNode)
+		}
+		if valueNode.Kind == yaml.AliasNode {
+			return fmt.Errorf("Cannot set a key under an alias.")
+		}
+		node = valueNode
+	}
+	return nil
+}
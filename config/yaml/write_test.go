@@ -0,0 +1,138 @@
+//
+// write_test.go
+//
+package yaml_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+//
+// Test Set and Save preserving comments and anchors.
+//
+func TestSave(t *testing.T) {
+	file, err := createTempYAMLFile(`# Service config.
+base: &base
+  timeout: 10 # seconds
+server:
+  <<: *base
+  port: 8080 # listen port
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	store.Set("server.port", 9090)
+	store.Set("server.tls.enabled", true)
+	if err := store.Save(file); err != nil {
+		t.Fatalf("Failed to save. Error: %v\n", err)
+	}
+
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read YAML file: %v", err)
+	}
+	content := string(bytes)
+	for _, want := range []string{"# Service config.", "&base", "<<: *base", "port: 9090 # listen port", "timeout: 10 # seconds"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Saved file does not contain %q.\n%s", want, content)
+		}
+	}
+
+	saved := yaml.NewStore(nil)
+	if err := saved.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load saved file. Error: %v\n", err)
+	}
+	if val := saved.GetInt("server.port"); val != 9090 {
+		t.Errorf("Failed to read server.port value. Expected: 9090, actual: %d\n", val)
+	}
+	if val := saved.GetBool("server.tls.enabled"); !val {
+		t.Errorf("Failed to read server.tls.enabled value. Expected: true, actual: %t\n", val)
+	}
+	if val := saved.GetInt("server.timeout"); val != 10 {
+		t.Errorf("Failed to read server.timeout value. Expected: 10, actual: %d\n", val)
+	}
+}
+
+
+//
+// Test Save keeping every document of a multi-document file.
+//
+func TestSave_MultiDocument(t *testing.T) {
+	file, err := createTempYAMLFile(`server:
+  port: 8080
+---
+server:
+  host: example.com
+`)
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	before := store.Config()
+	store.Set("server.host", "example.org")
+	store.Set("server.port", 9090)
+	if host := before["server"].(map[string]interface{})["host"]; host != "example.com" {
+		t.Errorf("Set modified the previous config. Expected: example.com, actual: %v\n", host)
+	}
+	if err := store.Save(file); err != nil {
+		t.Fatalf("Failed to save. Error: %v\n", err)
+	}
+
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read YAML file: %v", err)
+	}
+	want := "server:\n  port: 9090\n---\nserver:\n  host: example.org\n"
+	if content := string(bytes); content != want {
+		t.Errorf("Unexpected saved file. Expected:\n%s\nactual:\n%s\n", want, content)
+	}
+}
+
+
+//
+// Test Save ignoring the values set before a reload.
+//
+func TestSave_AfterReload(t *testing.T) {
+	file, err := createTempYAMLFile("server:\n  port: 8080\n")
+	if err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+	defer os.Remove(file)
+
+	store := yaml.NewStore(nil)
+	if err := store.InitSources(context.Background(), yaml.NewFileSource(file)); err != nil {
+		t.Fatalf("Failed to load. Error: %v\n", err)
+	}
+	store.Set("cache.size", 10)
+	if err := store.Reload(); err != nil {
+		t.Fatalf("Failed to reload. Error: %v\n", err)
+	}
+	if err := store.Save(file); err != nil {
+		t.Fatalf("Failed to save. Error: %v\n", err)
+	}
+
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read YAML file: %v", err)
+	}
+	if content := string(bytes); content != "server:\n  port: 8080\n" {
+		t.Errorf("Unexpected saved file.\n%s", content)
+	}
+}
@@ -4,19 +4,21 @@
 package yaml
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"gopkg.in/yaml.v3"
-	"io/ioutil"
+	"io/fs"
 	"log"
 	"os"
-	"strconv"
-	"strings"
 )
 
 
 var (
+	// Config is the tree of the default store.
+	// Prefer Set or Replace to change it. Values written into its maps in place are not seen by Watch, nor by the cached getters until the config changes.
 	Config  = make(map[string]interface{})
+
+	std = newDefaultStore()
 )
 
 
@@ -25,226 +27,112 @@ var (
 //
 func Init() error {
 	var yamlPaths []string
+	setFlags := NewFlagSource()
 	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
-	flag.Func("yaml", "Path to yaml config file (can be specified multiple times)", func(s string) error {
+	flag.Func("yaml", "Path or URL to yaml config file (can be specified multiple times)", func(s string) error {
 		yamlPaths = append(yamlPaths, s)
 		return nil
 	})
+	flag.Var(setFlags, "set", "Override a config value as key=value (can be specified multiple times)")
+	flag.Func("profile", "Comma separated profiles of the documents to load from multi-document files", func(s string) error {
+		SelectDocuments(DefaultDocumentKey, parseProfiles(s)...)
+		return nil
+	})
 	flag.Parse()
 
-	if len(yamlPaths) == 0 {
+	extraSourcesMu.Lock()
+	sources := append([]Source(nil), defaultSources...)
+	extraSourcesMu.Unlock()
+	if len(yamlPaths) == 0 && len(sources) == 0 {
 		return fmt.Errorf("Need at least one -yaml option.\n")
 	}
 
 	for _, path := range yamlPaths {
-		bytes, err := ioutil.ReadFile(path)
+		src, err := NewSource(path)
 		if err != nil {
 			return fmt.Errorf("%s\n", err.Error())
 		}
-		tmpConfig := make(map[string]interface{})
-		if err = yaml.Unmarshal(bytes, &tmpConfig); err != nil {
-			return fmt.Errorf("%s\n", err.Error())
-		}
-		mergeConfig(Config, tmpConfig)
+		sources = append(sources, src)
 	}
-	return nil
+	extraSourcesMu.Lock()
+	sources = append(sources, extraSources...)
+	extraSourcesMu.Unlock()
+	sources = append(sources, setFlags)
+
+	return std.initLoader(context.Background(), NewLoader(sources...))
 }
 
 
 //
 // Initialize from the specified file path.
 //
+// Deprecated: Use InitFromFS or InitSources, which return errors instead of exiting the process.
+//
 func InitFromFilePath(filePath string) {
-    bytes, err := ioutil.ReadFile(filePath)
-    if err != nil {
-        log.Fatalf("[FATAL] %s\n", err)
-    }
-    if err = yaml.Unmarshal(bytes, &Config); err != nil {
+    if err := InitSources(context.Background(), NewFileSource(filePath)); err != nil {
         log.Fatalf("[FATAL] %s\n", err)
     }
 }
 
 
+//
+// Initialize from the files in the file system.
+//
+func InitFromFS(fsys fs.FS, paths ...string) error {
+    return std.InitFromFS(fsys, paths...)
+}
+
+
 //
 // Get boolean value from key.
 //
-func GetBool(key string) bool {
-    v := getInterfaceValue(key)
-    if v == nil {
-        return false
-    }
-    switch result := v.(type) {
-    case bool:
-        return result
-    default:
-        return false
-    }
+func (s *Store) GetBool(key string) bool {
+    v, _ := GetFrom[bool](s, key)
+    return v
 }
 
 
 //
 // Get string value from key.
 //
-func GetString(key string) string {
-    v := getInterfaceValue(key)
-    if v == nil {
-        return ""
-    }
-    switch result := v.(type) {
-    case string:
-        return result
-    case bool:
-        return strconv.FormatBool(result)
-    case int:
-        return strconv.FormatInt(int64(result), 10)
-    case int8:
-        return strconv.FormatInt(int64(result), 10)
-    case int16:
-        return strconv.FormatInt(int64(result), 10)
-    case int32:
-        return strconv.FormatInt(int64(result), 10)
-    case int64:
-        return strconv.FormatInt(result, 10)
-    case float32:
-        return strconv.FormatFloat(float64(result), 'f', -1, 32)
-    case float64:
-        return strconv.FormatFloat(result, 'f', -1, 64)
-    case uint:
-        return strconv.FormatUint(uint64(result), 10)
-    case uint8:
-        return strconv.FormatUint(uint64(result), 10)
-    case uint16:
-        return strconv.FormatUint(uint64(result), 10)
-    case uint32:
-        return strconv.FormatUint(uint64(result), 10)
-    case uint64:
-        return strconv.FormatUint(result, 10)
-    default:
-        return ""
-    }
+func (s *Store) GetString(key string) string {
+    v, _ := GetFrom[string](s, key)
+    return v
 }
 
 
 //
 // Get int value from key.
 //
-func GetInt(key string) int {
-    v := getInterfaceValue(key)
-    if v == nil {
-        return 0
-    }
-    switch result := v.(type) {
-    case int:
-        return result
-    case int8:
-        return int(result)
-    case int16:
-        return int(result)
-    case int32:
-        return int(result)
-    case int64:
-        return int(result)
-    case uint:
-        return int(result)
-    case uint8:
-        return int(result)
-    case uint16:
-        return int(result)
-    case uint32:
-        return int(result)
-    case uint64:
-        return int(result)
-    case float32:
-        return int(result)
-    case float64:
-        return int(result)
-    default:
-        return 0
-    }
+func (s *Store) GetInt(key string) int {
+    v, _ := GetFrom[int](s, key)
+    return v
 }
 
 
 //
 // Get int64 value from key.
 //
-func GetInt64(key string) int64 {
-    v := getInterfaceValue(key)
-    if v == nil {
-        return 0
-    }
-    switch result := v.(type) {
-    case int64:
-        return result
-    case int:
-        return int64(result)
-    case int8:
-        return int64(result)
-    case int16:
-        return int64(result)
-    case int32:
-        return int64(result)
-    case uint8:
-        return int64(result)
-    case uint16:
-        return int64(result)
-    case uint32:
-        return int64(result)
-    case uint64:
-        return int64(result)
-    case float32:
-        return int64(result)
-    case float64:
-        return int64(result)
-    default:
-        return 0
-    }
+func (s *Store) GetInt64(key string) int64 {
+    v, _ := GetFrom[int64](s, key)
+    return v
 }
 
 
 //
 // Get float64 value from key.
 //
-func GetFloat64(key string) float64 {
-    v := getInterfaceValue(key)
-    if v == nil {
-        return 0
-    }
-    switch result := v.(type) {
-    case float64:
-        return result
-    case float32:
-        return float64(result)
-    case int:
-        return float64(result)
-    case int8:
-        return float64(result)
-    case int16:
-        return float64(result)
-    case int32:
-        return float64(result)
-    case int64:
-        return float64(result)
-    case uint:
-        return float64(result)
-    case uint8:
-        return float64(result)
-    case uint16:
-        return float64(result)
-    case uint32:
-        return float64(result)
-    case uint64:
-        return float64(result)
-    default: 
-        return 0
-    }
+func (s *Store) GetFloat64(key string) float64 {
+    v, _ := GetFrom[float64](s, key)
+    return v
 }
 
 
 //
 // Get array value from key.
 //
-func GetArray(key string) []interface{} {
-    v := getInterfaceValue(key)
+func (s *Store) GetArray(key string) []interface{} {
+    v := s.getInterfaceValue(key)
     if v == nil {
         return nil
     }
@@ -260,16 +148,18 @@ func GetArray(key string) []interface{} {
 //
 // Get array int value from key.
 //
-func GetArrayInt(key string) []int {
-    v := GetArray(key)
+func (s *Store) GetArrayInt(key string) []int {
+    v := s.GetArray(key)
     if v == nil {
         return nil
     }
     var result []int
     for _, vv := range v {
-        vvv, ok := vv.(int)
-        if ok {
-            result = append(result, vvv)
+        if vv == nil {
+            continue
+        }
+        if vvv, err := convertTo(vv, intType); err == nil {
+            result = append(result, vvv.(int))
         }
     }
     return result
@@ -279,43 +169,18 @@ func GetArrayInt(key string) []int {
 //
 // Get array string value from key.
 //
-func GetArrayString(key string) []string {
-    v := GetArray(key)
+func (s *Store) GetArrayString(key string) []string {
+    v := s.GetArray(key)
     if v == nil {
         return nil
     }
     var result []string
     for _, vv := range v {
-        switch vvv := vv.(type) {
-        case string:
-            result = append(result, vvv)
-        case bool:
-            result = append(result, strconv.FormatBool(vvv))
-        case int:
-            result = append(result, strconv.FormatInt(int64(vvv), 10))
-        case int8:
-            result = append(result, strconv.FormatInt(int64(vvv), 10))
-        case int16:
-            result = append(result, strconv.FormatInt(int64(vvv), 10))
-        case int32:
-            result = append(result, strconv.FormatInt(int64(vvv), 10))
-        case int64:
-            result = append(result, strconv.FormatInt(vvv, 10))
-        case float32:
-            result = append(result, strconv.FormatFloat(float64(vvv), 'f', -1, 32))
-        case float64:
-            result = append(result, strconv.FormatFloat(vvv, 'f', -1, 64))
-        case uint:
-            result = append(result, strconv.FormatUint(uint64(vvv), 10))
-        case uint8:
-            result = append(result, strconv.FormatUint(uint64(vvv), 10))
-        case uint16:
-            result = append(result, strconv.FormatUint(uint64(vvv), 10))
-        case uint32:
-            result = append(result, strconv.FormatUint(uint64(vvv), 10))
-        case uint64:
-            result = append(result, strconv.FormatUint(vvv, 10))
-        default:
+        if vv == nil {
+            continue
+        }
+        if vvv, err := convertTo(vv, stringType); err == nil {
+            result = append(result, vvv.(string))
         }
     }
     return result
@@ -325,26 +190,44 @@ func GetArrayString(key string) []string {
 //
 // Get interface value from key.
 //
-func getInterfaceValue(key string) interface{} {
-    var v interface{}
-    for i, k := range strings.Split(key, ".") {
-        if i == 0 {
-            v = Config[k]
-        } else {
-            switch result := v.(type) {
-            case map[interface{}]interface{}:
-            case map[string]interface{}:
-                v = result[k]
-            default:
-                v = nil
-                break
-            }
-        }
-    }
+func (s *Store) getInterfaceValue(key string) interface{} {
+    v, _ := s.get(key, nil, nil)
     return v
 }
 
 
+//
+// Load the config from the loader, and merge it into the current config.
+//
+func (s *Store) initLoader(ctx context.Context, l *Loader) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	result, err := l.load(ctx)
+	if err != nil {
+		return fmt.Errorf("%s\n", err.Error())
+	}
+
+	// Merge into the current config.
+	s.mu.RLock()
+	merged := newLoadResult()
+	merged.merge(&loadResult{config: copyConfig(*s.config), origins: s.origins, secrets: s.secrets})
+	s.mu.RUnlock()
+	merged.merge(result)
+	merged.digests = result.digests
+	pruneOrigins(merged.origins, merged.config)
+
+	if err := s.prepare(merged); err != nil {
+		return fmt.Errorf("%s\n", err.Error())
+	}
+	s.mu.Lock()
+	s.activate(merged)
+	s.loader = l
+	s.mu.Unlock()
+	return nil
+}
+
+
 //
 // Merge configurations.
 //
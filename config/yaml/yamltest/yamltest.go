@@ -0,0 +1,68 @@
+//
+// yamltest.go
+//
+// Package yamltest provides helpers to override config in unit tests.
+//
+package yamltest
+
+import (
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+	goyaml "gopkg.in/yaml.v3"
+)
+
+
+//
+// Install the config as the default config until the test ends.
+// The default config is shared by the whole process, so tests using it must not call t.Parallel.
+// Use New for parallel tests.
+//
+func Set(t testing.TB, config map[string]interface{}) {
+	t.Helper()
+	previous := yaml.Default().Replace(config)
+	t.Cleanup(func() {
+		yaml.Default().Replace(previous)
+	})
+}
+
+
+//
+// Install the YAML text as the default config until the test ends.
+//
+func LoadString(t testing.TB, text string) {
+	t.Helper()
+	Set(t, parse(t, text))
+}
+
+
+//
+// New store holding the config.
+// The store is independent of the default config, so it is safe for parallel tests.
+//
+func New(t testing.TB, config map[string]interface{}) *yaml.Store {
+	t.Helper()
+	return yaml.NewStore(config)
+}
+
+
+//
+// New store holding the YAML text.
+//
+func NewString(t testing.TB, text string) *yaml.Store {
+	t.Helper()
+	return yaml.NewStore(parse(t, text))
+}
+
+
+//
+// Parse the YAML text, and fail the test on error.
+//
+func parse(t testing.TB, text string) map[string]interface{} {
+	t.Helper()
+	config := make(map[string]interface{})
+	if err := goyaml.Unmarshal([]byte(text), &config); err != nil {
+		t.Fatalf("Failed to parse YAML. Error: %v\n", err)
+	}
+	return config
+}
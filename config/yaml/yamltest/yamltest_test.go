@@ -0,0 +1,47 @@
+//
+// yamltest_test.go
+//
+package yamltest_test
+
+import (
+	"testing"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+	"github.com/k4k3ru-hub/go/config/yaml/yamltest"
+)
+
+
+//
+// Test LoadString restoring the previous config.
+//
+func TestLoadString(t *testing.T) {
+	yamltest.Set(t, map[string]interface{}{"key1": "outer"})
+
+	t.Run("override", func(t *testing.T) {
+		yamltest.LoadString(t, "key1: inner\n")
+		if val := yaml.GetString("key1"); val != "inner" {
+			t.Errorf("Failed to read key1 value. Expected: inner, actual: %s\n", val)
+		}
+	})
+
+	if val := yaml.GetString("key1"); val != "outer" {
+		t.Errorf("Failed to restore key1 value. Expected: outer, actual: %s\n", val)
+	}
+}
+
+
+//
+// Test NewString in parallel subtests.
+//
+func TestNewString_Parallel(t *testing.T) {
+	for _, want := range []string{"a", "b", "c"} {
+		want := want
+		t.Run(want, func(t *testing.T) {
+			t.Parallel()
+			store := yamltest.NewString(t, "key1: " + want + "\n")
+			if val := store.GetString("key1"); val != want {
+				t.Errorf("Failed to read key1 value. Expected: %s, actual: %s\n", want, val)
+			}
+		})
+	}
+}
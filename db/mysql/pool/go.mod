@@ -0,0 +1,17 @@
+module github.com/k4k3ru-hub/go/db/mysql/pool
+
+go 1.21.6
+
+require (
+	github.com/go-sql-driver/mysql v1.9.0
+	github.com/k4k3ru-hub/go/config/yaml v0.1.0
+)
+
+require (
+	filippo.io/edwards25519 v1.1.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// Use the config/yaml module in this repository while developing both.
+// Released versions resolve the config/yaml/v0.1.0 tag.
+replace github.com/k4k3ru-hub/go/config/yaml => ../../../config/yaml
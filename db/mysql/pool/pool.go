@@ -0,0 +1,247 @@
+//
+// pool.go
+//
+// Package pool opens MySQL connection pools from the "database" section of the config.
+//
+//   database:
+//     host: db.local
+//     port: 3306
+//     user: app
+//     password_file: /run/secrets/db_password  # or password_env: DB_PASSWORD
+//     name: app
+//     params:
+//       charset: utf8mb4
+//     tls:
+//       enabled: true
+//       ca_file: /etc/ssl/db-ca.pem
+//     max_open_conns: 20
+//     max_idle_conns: 10
+//     conn_max_lifetime: 30m
+//     conn_max_idle_time: 5m
+//
+package pool
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/k4k3ru-hub/go/config/yaml"
+)
+
+
+const (
+	DefaultKey  = "database"
+	DefaultPort = 3306
+)
+
+
+//
+// Config is the database section.
+// The password is read from password_file, password_env or password, and only one of them can be set.
+//
+type Config struct {
+	Host            string            `yaml:"host" validate:"required"`
+	Port            int               `yaml:"port" validate:"min=1,max=65535"`
+	User            string            `yaml:"user" validate:"required"`
+	Password        string            `yaml:"password"`
+	PasswordFile    string            `yaml:"password_file"`
+	PasswordEnv     string            `yaml:"password_env"`
+	Name            string            `yaml:"name"`
+	Params          map[string]string `yaml:"params"`
+	TLS             TLSConfig         `yaml:"tls"`
+	MaxOpenConns    int               `yaml:"max_open_conns" validate:"min=0"`
+	MaxIdleConns    int               `yaml:"max_idle_conns" validate:"min=0"`
+	ConnMaxLifetime time.Duration     `yaml:"conn_max_lifetime" validate:"min=0"`
+	ConnMaxIdleTime time.Duration     `yaml:"conn_max_idle_time" validate:"min=0"`
+}
+type TLSConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+
+//
+// Open a connection pool from the section of the default config.
+//
+func Open(key string) (*sql.DB, error) {
+	return OpenFrom(yaml.Default(), key)
+}
+
+
+//
+// Open a connection pool from the section of the store.
+// Connections are made lazily, so call Ping to check the server.
+//
+func OpenFrom(s *yaml.Store, key string) (*sql.DB, error) {
+	c, err := LoadConfig(s, key)
+	if err != nil {
+		return nil, err
+	}
+	return c.Open()
+}
+
+
+//
+// Load and validate the section of the store.
+//
+func LoadConfig(s *yaml.Store, key string) (*Config, error) {
+	c := &Config{
+		Port: DefaultPort,
+	}
+	if err := s.Decode(key, c); err != nil {
+		return nil, err
+	}
+	if err := c.validatePassword(key); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+
+//
+// Open a connection pool tuned by the config.
+// Zero values keep the database/sql defaults, such as 2 idle connections.
+//
+func (c *Config) Open() (*sql.DB, error) {
+	mysqlConfig, err := c.MySQLConfig()
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(mysqlConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	if c.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(c.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+	return db, nil
+}
+
+
+//
+// Get the driver config.
+// parseTime is enabled unless the params set it, since the account model scans time.Time columns.
+//
+func (c *Config) MySQLConfig() (*mysql.Config, error) {
+	params := url.Values{}
+	params.Set("parseTime", "true")
+	for k, v := range c.Params {
+		params.Set(k, v)
+	}
+	mysqlConfig, err := mysql.ParseDSN("/?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("Invalid database params. (error: %s)", err)
+	}
+
+	password, err := c.password()
+	if err != nil {
+		return nil, err
+	}
+	mysqlConfig.User = c.User
+	mysqlConfig.Passwd = password
+	mysqlConfig.Net = "tcp"
+	mysqlConfig.Addr = net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	mysqlConfig.DBName = c.Name
+
+	if c.TLS.Enabled {
+		tlsConfig, err := c.TLS.tlsConfig(c.Host)
+		if err != nil {
+			return nil, err
+		}
+		mysqlConfig.TLS = tlsConfig
+	}
+	return mysqlConfig, nil
+}
+
+
+//
+// Check that at most one password source is set.
+//
+func (c *Config) validatePassword(key string) error {
+	var set []string
+	for name, value := range map[string]string{"password": c.Password, "password_file": c.PasswordFile, "password_env": c.PasswordEnv} {
+		if value != "" {
+			set = append(set, name)
+		}
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("Need only one of password, password_file and password_env. (key: %s)", key)
+	}
+	return nil
+}
+
+
+//
+// Get the password from the file, the environment variable or the config.
+//
+func (c *Config) password() (string, error) {
+	switch {
+	case c.PasswordFile != "":
+		bytes, err := os.ReadFile(c.PasswordFile)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read the database password file. (path: %s, error: %s)", c.PasswordFile, err)
+		}
+		return strings.TrimRight(string(bytes), "\r\n"), nil
+	case c.PasswordEnv != "":
+		password, ok := os.LookupEnv(c.PasswordEnv)
+		if !ok {
+			return "", fmt.Errorf("Database password environment variable is not set. (name: %s)", c.PasswordEnv)
+		}
+		return password, nil
+	default:
+		return c.Password, nil
+	}
+}
+
+
+//
+// Get the TLS config.
+// The server name defaults to the host.
+//
+func (c *TLSConfig) tlsConfig(host string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName: c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+
+	if c.CAFile != "" {
+		bytes, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read the CA file. (path: %s, error: %s)", c.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bytes) {
+			return nil, fmt.Errorf("No certificates in the CA file. (path: %s)", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load the client certificate. (cert: %s, key: %s, error: %s)", c.CertFile, c.KeyFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,128 @@
+//
+// pool_test.go
+//
+package pool_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/k4k3ru-hub/go/config/yaml"
+	"github.com/k4k3ru-hub/go/config/yaml/yamltest"
+	"github.com/k4k3ru-hub/go/db/mysql/pool"
+)
+
+
+//
+// Test OpenFrom building the driver config and tuning the pool.
+//
+func TestOpenFrom(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("s3cret\n"), 0600); err != nil {
+		t.Fatalf("Failed to write password file: %v", err)
+	}
+	store := yamltest.NewString(t, `
+database:
+  host: db.local
+  user: app
+  password_file: `+passwordFile+`
+  name: accounts
+  params:
+    autocommit: "true"
+    timeout: 5s
+  tls:
+    enabled: true
+  max_open_conns: 20
+  conn_max_lifetime: 30m
+`)
+
+	c, err := pool.LoadConfig(store, "database")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if c.Port != pool.DefaultPort || c.ConnMaxLifetime != 30*time.Minute {
+		t.Errorf("Unexpected config: %+v", c)
+	}
+	mysqlConfig, err := c.MySQLConfig()
+	if err != nil {
+		t.Fatalf("Failed to get driver config: %v", err)
+	}
+	if mysqlConfig.Addr != "db.local:3306" || mysqlConfig.Passwd != "s3cret" || mysqlConfig.DBName != "accounts" {
+		t.Errorf("Unexpected driver config: %+v", mysqlConfig)
+	}
+	if !mysqlConfig.ParseTime || mysqlConfig.Timeout != 5*time.Second || mysqlConfig.Params["autocommit"] != "true" {
+		t.Errorf("Unexpected driver params: %+v", mysqlConfig)
+	}
+	if mysqlConfig.TLS == nil || mysqlConfig.TLS.ServerName != "db.local" {
+		t.Errorf("Expected TLS for db.local, actual: %+v", mysqlConfig.TLS)
+	}
+
+	db, err := pool.OpenFrom(store, "database")
+	if err != nil {
+		t.Fatalf("Failed to open: %v", err)
+	}
+	defer db.Close()
+	if db.Stats().MaxOpenConnections != 20 {
+		t.Errorf("Expected 20 max open connections, actual: %d", db.Stats().MaxOpenConnections)
+	}
+}
+
+
+//
+// Test LoadConfig rejecting missing fields and multiple passwords.
+//
+func TestLoadConfig_Invalid(t *testing.T) {
+	store := yamltest.NewString(t, `
+database:
+  port: 70000
+`)
+	_, err := pool.LoadConfig(store, "database")
+	var errs yaml.ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Errorf("Expected errors for host, user and port, actual: %v", err)
+	}
+
+	store = yamltest.NewString(t, `
+database:
+  host: db.local
+  user: app
+  password: a
+  password_env: DB_PASSWORD
+`)
+	if _, err := pool.LoadConfig(store, "database"); err == nil {
+		t.Errorf("Expected an error for multiple passwords.")
+	}
+
+	// An explicit zero port does not fall back to the default.
+	store = yamltest.NewString(t, `
+database:
+  host: db.local
+  user: app
+  port: 0
+`)
+	_, err = pool.LoadConfig(store, "database")
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Key != "database.port" {
+		t.Errorf("Expected an error for port, actual: %v", err)
+	}
+}
+
+
+//
+// Test the password from environment variables.
+//
+func TestMySQLConfig_PasswordEnv(t *testing.T) {
+	t.Setenv("POOL_TEST_PASSWORD", "from-env")
+	c := &pool.Config{Host: "db.local", Port: 3306, User: "app", PasswordEnv: "POOL_TEST_PASSWORD"}
+	mysqlConfig, err := c.MySQLConfig()
+	if err != nil || mysqlConfig.Passwd != "from-env" {
+		t.Errorf("Expected the password from env, actual: %v, error: %v", mysqlConfig, err)
+	}
+
+	c.PasswordEnv = "POOL_TEST_UNSET_PASSWORD"
+	if _, err := c.MySQLConfig(); err == nil {
+		t.Errorf("Expected an error for an unset environment variable.")
+	}
+}